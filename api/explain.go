@@ -0,0 +1,94 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// defaultSimilarPasswords is the number of similar passwords explained unless requested otherwise,
+// maxSimilarPasswords the maximal number that may be requested.
+const defaultSimilarPasswords, maxSimilarPasswords = 10, 25
+
+// ExplainToken is the bearer token authorizing requests for GET /api/explain. If it is empty, the endpoint is disabled.
+var ExplainToken string
+
+// SimilarPasswordResult is a struct representing an entry of the password list similar to the password
+type SimilarPasswordResult struct {
+	Password   string  `json:"password"`
+	List       string  `json:"list"`
+	Rank       int     `json:"rank"`
+	Distance   float64 `json:"distance"`
+	Similarity float64 `json:"similarity"`
+	Transform  string  `json:"transform,omitempty"`
+}
+
+// Explanation is a struct representing the result of a password together with details for admin tooling,
+// which are not part of the public result
+type Explanation struct {
+	Result
+	SimilarPasswords []SimilarPasswordResult `json:"similarPasswords"`
+}
+
+// Explain calculates the result of the given password together with the k entries of the password list most similar to it
+// (scanning the password list only once for both)
+func Explain(password string, language string, k int) Explanation {
+	similarPasswords := metric.CalculateSimilarPasswords(password, k)
+	m := calculateMetrics(password, language, similarPasswords)
+
+	explanation := Explanation{Result: resultOf(m, password, language), SimilarPasswords: []SimilarPasswordResult{}}
+	for _, similar := range similarPasswords {
+		explanation.SimilarPasswords = append(explanation.SimilarPasswords, SimilarPasswordResult{
+			Password:   similar.Password,
+			List:       similar.Label,
+			Rank:       similar.Rank,
+			Distance:   similar.Distance,
+			Similarity: math.Round(similar.Similarity*100) / 100,
+			Transform:  similar.Transform})
+	}
+	return explanation
+}
+
+// ExplainHandler takes incoming requests for GET /api/explain with the same headers as RequestHandler and writes
+// the explanation of the password. The query parameter k sets the number of similar passwords (default 10, at most 25).
+// Requests must have the header "Authorization: Bearer <ExplainToken>".
+func ExplainHandler(w http.ResponseWriter, r *http.Request) {
+	if ExplainToken == "" {
+		// without a token this instance does not explain passwords
+		w.WriteHeader(http.StatusNotFound)
+		log.Println("Error: Explanation requested, but no explain token configured")
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+ExplainToken)) != 1 {
+		w.WriteHeader(http.StatusUnauthorized)
+		log.Println("Error: Explanation requested without valid explain token")
+		return
+	}
+
+	k := defaultSimilarPasswords
+	if value := r.URL.Query().Get("k"); value != "" {
+		var err error
+		k, err = strconv.Atoi(value)
+		if err != nil || k < 1 || k > maxSimilarPasswords {
+			w.WriteHeader(http.StatusBadRequest)
+			log.Println("Error: Number of similar passwords invalid")
+			return
+		}
+	}
+
+	password, language, ok := readInput(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(Explain(password, language, k)); err != nil {
+		log.Printf("Error: Could not encode explanation: %s\n", err)
+	}
+}
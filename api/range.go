@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// minPadding and maxPadding are the bounds of the number of entries in a padded range response
+// (like the Pwned Passwords API, padded responses contain between 800 and 1000 entries).
+const minPadding, maxPadding = 800, 1000
+
+// hashPrefixPattern matches a valid hash prefix (5 hex characters).
+var hashPrefixPattern = regexp.MustCompile("^[0-9A-Fa-f]{5}$")
+
+// RangeHandler takes incoming requests for GET /range/{prefix} and writes all hash suffixes of metric.BreachCorpus
+// starting with the given prefix in the format of the Pwned Passwords range API ("SUFFIX:COUNT" per line).
+// If the request has the header "Add-Padding: true", the response is padded with random suffixes with a count of 0.
+func RangeHandler(w http.ResponseWriter, r *http.Request) {
+	prefix := path.Base(r.URL.Path)
+	if !hashPrefixPattern.MatchString(prefix) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("The hash prefix was not in a valid format"))
+		return
+	}
+
+	if metric.BreachCorpus == nil {
+		// without a corpus this instance can not act as range API
+		w.WriteHeader(http.StatusServiceUnavailable)
+		log.Println("Error: Range requested, but no breach corpus configured")
+		return
+	}
+
+	prefix = strings.ToUpper(prefix)
+	entries, err := metric.BreachCorpus.Range(prefix)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Printf("Error: Could not read range %s from breach corpus: %s\n", prefix, err)
+		return
+	}
+
+	if r.Header.Get("Add-Padding") == "true" {
+		entries, err = padRange(entries)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			log.Printf("Error: Could not pad range: %s\n", err)
+			return
+		}
+	}
+
+	var body bytes.Buffer
+	for i, entry := range entries {
+		if i > 0 {
+			body.WriteString("\r\n")
+		}
+		fmt.Fprintf(&body, "%s:%d", entry.Suffix, entry.Count)
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(body.Bytes())
+	if err != nil {
+		log.Printf("Error: Could not write range: %s\n", err)
+	}
+}
+
+// padRange adds random suffixes with a count of 0 to the given entries, so that the total number of entries
+// is random between minPadding and maxPadding. Entries are returned ordered by suffix.
+func padRange(entries []metric.RangeEntry) ([]metric.RangeEntry, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(maxPadding-minPadding+1))
+	if err != nil {
+		return nil, err
+	}
+	total := minPadding + int(n.Int64())
+
+	known := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		known[entry.Suffix] = true
+	}
+
+	// a suffix consists of the remaining 35 hex characters of the hash
+	suffix := make([]byte, 18)
+	for len(entries) < total {
+		if _, err := rand.Read(suffix); err != nil {
+			return nil, err
+		}
+		padding := strings.ToUpper(hex.EncodeToString(suffix))[:35]
+		if !known[padding] {
+			known[padding] = true
+			entries = append(entries, metric.RangeEntry{Suffix: padding, Count: 0})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Suffix < entries[j].Suffix })
+	return entries, nil
+}
@@ -22,44 +22,147 @@ type Result struct {
 	Complexity     MetricResult `json:"complexity"`
 	Predictability MetricResult `json:"predictability"`
 	Strength       MetricResult `json:"strength"`
+	Guesses        GuessResult  `json:"guesses"`
 }
 
-// CalculateMetrics calculates the results length, complexity, predictability, total strength, corresponding membership grades and the mostSimilarPassword for a given password string
-func CalculateMetrics(password string) (length, complexity, predictability, strength float64, LList, CList, PList []float64, mostSimilarPassword string) {
+// CrackTimeResult is a struct representing the estimated crack time of a password in an attack scenario provided to a client
+type CrackTimeResult struct {
+	Scenario string  `json:"scenario"`
+	Seconds  float64 `json:"seconds"`
+	Display  string  `json:"display"`
+}
+
+// GuessResult is a struct representing the estimated number of guesses and crack times of a password provided to a client
+type GuessResult struct {
+	Guesses      float64           `json:"guesses"`
+	GuessesLog10 float64           `json:"guessesLog10"`
+	CrackTimes   []CrackTimeResult `json:"crackTimes"`
+	Hint         string            `json:"hint"`
+}
+
+// Metrics is a struct representing all metric values, their membership grades and the total strength calculated for a password
+type Metrics struct {
+	Length              float64
+	EffectiveLength     float64
+	Complexity          float64
+	EffectiveComplexity float64
+	Repetition          metric.Repetition
+	Predictability      float64
+	Similarity          float64
+	SimilarVariant      metric.Similarity
+	Phonetic            metric.PhoneticMatch
+	Segmentation        metric.Segmentation
+	KeyboardWalks       []metric.KeyboardWalk
+	Patterns            []metric.Match
+	Structure           metric.Structure
+	Derivation          metric.Derivation
+	BreachCount         int
+	Guesses             float64
+	CrackTimes          []metric.CrackTime
+	Strength            float64
+	LList               []float64
+	CList               []float64
+	PList               []float64
+	BList               []float64
+	MostSimilarPassword string
+}
+
+// CalculateMetrics calculates the results length, complexity, predictability, breach prevalence, estimated guesses and crack times, total strength, corresponding membership grades and the mostSimilarPassword for a given password string
+// (the language selects the phonetic algorithm of predictability)
+func CalculateMetrics(password string, language string) Metrics {
+	return calculateMetrics(password, language, metric.CalculateSimilarPasswords(password, 1))
+}
+
+// calculateMetrics calculates the metrics like CalculateMetrics, taking the similarity from the given entries of the
+// password list most similar to the password (see metric.CalculateSimilarPasswords)
+func calculateMetrics(password string, language string, similarPasswords []metric.SimilarPassword) (m Metrics) {
 	// calculate the main metrics
-	length = float64(metric.CalculateLength(password))
-	complexity = metric.CalculateComplexity(password)
-	predictability, mostSimilarPassword = metric.CalculatePredictability(password)
+	m.Length = float64(metric.CalculateLength(password))
+	m.Complexity = metric.CalculateComplexity(password)
+	m.Repetition = metric.CalculateRepetition(password)
+	m.SimilarVariant = metric.SimilarityOf(similarPasswords)
+	m.Similarity, m.MostSimilarPassword = m.SimilarVariant.Score, m.SimilarVariant.MostSimilarPassword
+	m.Phonetic = metric.CalculatePhoneticSimilarity(password, language)
+	m.Segmentation = metric.CalculateSegmentation(password)
+	m.KeyboardWalks, m.Patterns = m.Segmentation.KeyboardWalks, m.Segmentation.Patterns
+	m.Structure = metric.CalculateStructure(password)
+	m.Derivation = metric.CalculateMangling(password)
+	m.BreachCount = metric.CalculateBreach(password)
+
+	// a password is as predictable as its most predictable aspect
+	m.Predictability = math.Max(m.Similarity, math.Max(m.Segmentation.Predictability(), m.Derivation.Predictability()))
+	m.Predictability = math.Max(m.Predictability, m.Phonetic.Score)
+
+	// estimate how many guesses and how much time an attacker needs
+	m.Guesses = metric.EstimateGuesses(password, m.Segmentation)
+	m.CrackTimes = metric.CalculateCrackTimes(m.Guesses)
+
+	// repeats and sequences only count as little length and complexity
+	// (complexity model v2 already accounts for repeats in the full password),
+	// character sets placed the way most people place them do not count as complexity
+	m.EffectiveLength = m.Repetition.EffectiveLength
+	m.EffectiveComplexity = math.Min(m.Complexity, m.Repetition.EffectiveComplexity())
+	m.EffectiveComplexity = math.Min(m.EffectiveComplexity, metric.CalculateStructuralComplexity(password, m.Structure))
 
 	// calculate memberships of metric values
-	LList = fuzzy.CalculateMembershipGradesForLength(length)
-	CList = fuzzy.CalculateMembershipGradesForComplexity(complexity)
-	PList = fuzzy.CalculateMembershipGradesForPredictability(predictability)
+	m.LList = fuzzy.CalculateMembershipGradesForLength(m.EffectiveLength)
+	if metric.ComplexityModel == metric.ComplexityModelV2 {
+		m.CList = fuzzy.CalculateMembershipGradesForComplexityV2(m.EffectiveComplexity)
+	} else {
+		m.CList = fuzzy.CalculateMembershipGradesForComplexity(m.EffectiveComplexity)
+	}
+	m.PList = fuzzy.CalculateMembershipGradesForPredictability(m.Predictability)
+	m.BList = fuzzy.CalculateMembershipGradesForBreach(float64(m.BreachCount))
+	if fes.IsModelLoaded() {
+		// a loaded model brings its own membership functions
+		m.LList, m.CList, m.PList, m.BList = fes.Fuzzify(m.EffectiveLength, m.EffectiveComplexity, m.Predictability, float64(m.BreachCount))
+	}
 
 	// calculate overall strength
-	strength = fes.GetStrengthByMembershipGrades(LList, CList, PList)
+	m.Strength = fes.GetStrengthByMembershipGrades(m.LList, m.CList, m.PList, m.BList)
 	return
 }
 
-// CalculateResult calculates the results and provides a Result struct representation of the length, complexity, predictability and total strength for a given password string
+// CalculateResult calculates the results and provides a Result struct representation of the length, complexity, predictability, total strength and estimated guesses for a given password string
 func CalculateResult(password string, language string) Result {
-	length, complexity, predictability, strength, LList, CList, PList, mostSimilarPassword := CalculateMetrics(password)
+	return resultOf(CalculateMetrics(password, language), password, language)
+}
 
+// resultOf provides a Result struct representation of the given metrics of a password
+func resultOf(m Metrics, password string, language string) Result {
 	return Result{
-		Length:         getLengthResult(length, LList, language),
-		Complexity:     getComplexResult(complexity, CList, password, language),
-		Predictability: getPredictabilityResult(predictability, PList, mostSimilarPassword, language),
-		Strength:       getStrengthResult(strength, language)}
+		Length:         getLengthResult(m, language),
+		Complexity:     getComplexResult(m, password, language),
+		Predictability: getPredictabilityResult(m, language),
+		Strength:       getStrengthResult(m.Strength, m.BreachCount, language),
+		Guesses:        getGuessResult(m, language)}
 }
 
-// getStrengthScore provides a MetricResult struct representation of given total strength
-func getStrengthResult(strength float64, language string) MetricResult {
+// getGuessResult provides a GuessResult struct representation of the estimated guesses and crack times of given metrics
+func getGuessResult(m Metrics, language string) GuessResult {
+	crackTimes := make([]CrackTimeResult, len(m.CrackTimes))
+	for i, crackTime := range m.CrackTimes {
+		crackTimes[i] = CrackTimeResult{
+			Scenario: crackTime.Scenario,
+			Seconds:  crackTime.Seconds,
+			Display:  metric.FormatDuration(crackTime.Seconds, language)}
+	}
+
+	return GuessResult{
+		Guesses:      m.Guesses,
+		GuessesLog10: math.Round(math.Log10(m.Guesses)*100) / 100,
+		CrackTimes:   crackTimes,
+		Hint:         metric.GetHintGuesses(m.Guesses, m.CrackTimes, language)}
+}
+
+// getStrengthScore provides a MetricResult struct representation of given total strength and breach prevalence
+func getStrengthResult(strength float64, breachCount int, language string) MetricResult {
 	strengthScore := int(math.Round(strength))
 	strengthMsg := strengthResultToText(strength, language)
 	return MetricResult{
 		Score:   strengthScore,
 		Message: strengthMsg,
-		Hint:    ""}
+		Hint:    metric.GetHintBreach(breachCount, language)}
 }
 
 // strengthResultToText turns a strength level in float64 (percent, e.g. 20.43523) to the corresponding set name.
@@ -100,8 +203,8 @@ func strengthResultToText(level float64, language string) string {
 	return textResult
 }
 
-// getLengthScore provides a MetricResult struct representation of the given length and length membership grades
-func getLengthResult(length float64, LList []float64, language string) MetricResult {
+// getLengthScore provides a MetricResult struct representation of the effective length and length membership grades of given metrics
+func getLengthResult(m Metrics, language string) MetricResult {
 	// A password is very long (->100%) when it has 26 or more characters. => maxVal=26
 	var linguisticVars []string
 	if language == "de" {
@@ -110,13 +213,13 @@ func getLengthResult(length float64, LList []float64, language string) MetricRes
 		linguisticVars = []string{"very short", "short", "medium", "long", "very long"}
 	}
 
-	hint := metric.GetHintLength(length, language)
-	return generateMetricResult(length, 26, LList, linguisticVars, hint, language)
+	hint := metric.GetHintEffectiveLength(m.Length, m.Repetition, language)
+	return generateMetricResult(m.EffectiveLength, 26, m.LList, linguisticVars, hint, language)
 }
 
-// getComplexScore provides a MetricResult struct representation of the given complexity and complecity membership grades
-func getComplexResult(complexity float64, CList []float64, password string, language string) MetricResult {
-	// A password is very complex (->100%) when it has 26 or more characters. => maxVal=677
+// getComplexScore provides a MetricResult struct representation of the effective complexity and complecity membership grades of given metrics
+func getComplexResult(m Metrics, password string, language string) MetricResult {
+	// A password is very complex (->100%) when it reaches the maximum complexity of the model (v1: 677, v2: 100)
 	var linguisticVars []string
 	if language == "de" {
 		linguisticVars = []string{"sehr einfach", "einfach", "mittelmäßig", "komplex", "sehr komplex"}
@@ -124,12 +227,21 @@ func getComplexResult(complexity float64, CList []float64, password string, lang
 		linguisticVars = []string{"very simple", "simple", "medium", "complex", "very complex"}
 	}
 
-	hint := metric.GetHintComplexity(password, complexity, language)
-	return generateMetricResult(complexity, 677, CList, linguisticVars, hint, language)
+	hint := metric.GetHintComplexity(password, m.EffectiveComplexity, language)
+	if repetitionHint := metric.GetHintRepetitionComplexity(m.Repetition, language); m.EffectiveComplexity < m.Complexity && repetitionHint != "" {
+		hint += " " + repetitionHint
+	}
+	if diversityHint := metric.GetHintDiversity(password, language); diversityHint != "" {
+		hint += " " + diversityHint
+	}
+	if structureHint := metric.GetHintStructure(m.Structure, language); structureHint != "" {
+		hint += " " + structureHint
+	}
+	return generateMetricResult(m.EffectiveComplexity, metric.MaxComplexity(), m.CList, linguisticVars, hint, language)
 }
 
-// getPredictabilityScore provides a MetricResult struct representation of the given predictability and predictability membership grades
-func getPredictabilityResult(predictability float64, PList []float64, mostSimilarPassword string, language string) MetricResult {
+// getPredictabilityScore provides a MetricResult struct representation of the predictability and predictability membership grades of given metrics
+func getPredictabilityResult(m Metrics, language string) MetricResult {
 	var linguisticVars []string
 	if language == "de" {
 		linguisticVars = []string{"schwer vorherzusagen", "mittelmäßig", "einfach vorherzusagen"}
@@ -137,8 +249,26 @@ func getPredictabilityResult(predictability float64, PList []float64, mostSimila
 		linguisticVars = []string{"hard to predict", "medium", "easy to predict"}
 	}
 
-	hint := metric.GetHintPredictability(mostSimilarPassword, predictability, language)
-	return generateMetricResult(predictability, 100, PList, linguisticVars, hint, language)
+	hint := metric.GetHintPredictability(m.MostSimilarPassword, m.Similarity, language)
+	if phoneticHint := metric.GetHintPhonetic(m.Phonetic, m.SimilarVariant, language); phoneticHint != "" {
+		hint += " " + phoneticHint
+	}
+	if transformHint := metric.GetHintSimilarityTransform(m.SimilarVariant, language); transformHint != "" {
+		hint += " " + transformHint
+	}
+	if manglingHint := metric.GetHintMangling(m.Derivation, language); manglingHint != "" {
+		hint += " " + manglingHint
+	}
+	if segmentationHint := metric.GetHintSegmentation(m.Segmentation, m.MostSimilarPassword, language); segmentationHint != "" {
+		hint += " " + segmentationHint
+	}
+	if keyboardHint := metric.GetHintKeyboard(m.KeyboardWalks, language); keyboardHint != "" {
+		hint += " " + keyboardHint
+	}
+	if patternHint := metric.GetHintPatterns(m.Segmentation, language); patternHint != "" {
+		hint += " " + patternHint
+	}
+	return generateMetricResult(m.Predictability, 100, m.PList, linguisticVars, hint, language)
 }
 
 // generateMetricResult returns a MetricResult struct representation of a metric,
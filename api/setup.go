@@ -3,8 +3,12 @@ package api
 import (
 	"bufio"
 	"log"
+	"os"
 	"path"
+	"strconv"
+	"strings"
 
+	"github.com/tupass/tupass-backend/fes"
 	"github.com/tupass/tupass-backend/metric"
 
 	rice "github.com/GeertJohan/go.rice"
@@ -30,6 +34,9 @@ func SetupPasswordByFile(pwlist string) {
 		log.Panicf("Could not open passwordlist %s\n", err)
 	}
 
+	// label the appended entries with the name of the list
+	metric.PasswordListSources = append(metric.PasswordListSources, metric.PasswordListSource{Label: filename, Start: len(metric.PasswordList)})
+
 	// iterate over all lines in file and append them to metric.PasswordList
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -49,3 +56,145 @@ func SetupPasswordByFile(pwlist string) {
 
 	log.Printf("Reading password list done.\n")
 }
+
+// SetupBreachCorpus opens the local SHA-1 hash corpus (e.g. an offline Pwned Passwords download ordered by hash)
+// given by environment variable TUPASS_BREACH_CORPUS and sets it as metric.BreachCorpus for usage in the breach check later on.
+// If the variable is not set, the breach check is disabled.
+func SetupBreachCorpus() {
+	filepath := os.Getenv("TUPASS_BREACH_CORPUS")
+	if filepath == "" {
+		log.Printf("No breach corpus configured, breach check disabled.\n")
+		return
+	}
+
+	corpus, err := metric.OpenHashCorpus(filepath)
+	if err != nil {
+		log.Panicf("Could not open breach corpus %s\n", err)
+	}
+	metric.BreachCorpus = corpus
+
+	log.Printf("Opening breach corpus done.\n")
+}
+
+// SetupLeetTable loads the leetspeak substitution table given by environment variable TUPASS_LEET_TABLE (see metric.ParseLeetTable).
+// If the variable is not set, metric.DefaultLeetTable is used.
+func SetupLeetTable() {
+	filepath := os.Getenv("TUPASS_LEET_TABLE")
+	if filepath == "" {
+		return
+	}
+
+	err := metric.LoadLeetTable(filepath)
+	if err != nil {
+		log.Panicf("Could not load leet table %s\n", err)
+	}
+
+	log.Printf("Loading leet table done.\n")
+}
+
+// SetupCostModel sets metric.DistanceCostModel to a metric.KeyboardCostModel for the keyboard layouts given by environment variable
+// TUPASS_KEYBOARD_LAYOUTS as comma separated list of names (e.g. "QWERTZ,QWERTY"). If the variable is not set, all layouts are used.
+func SetupCostModel() {
+	value := os.Getenv("TUPASS_KEYBOARD_LAYOUTS")
+	if value == "" {
+		return
+	}
+
+	var layouts []string
+	for _, layout := range strings.Split(value, ",") {
+		layouts = append(layouts, strings.TrimSpace(layout))
+	}
+
+	model, err := metric.NewKeyboardCostModel(layouts...)
+	if err != nil {
+		log.Panicf("Could not set up cost model %s\n", err)
+	}
+	metric.DistanceCostModel = model
+
+	log.Printf("Setting up cost model done.\n")
+}
+
+// SetupAttackScenarios sets metric.AttackScenarios to the attack scenarios given by environment variable TUPASS_ATTACK_SCENARIOS
+// as comma separated list of name=guesses per second pairs (e.g. "online-throttled=0.0277,offline-fast-hash=1e10").
+// If the variable is not set, the default scenarios are used.
+func SetupAttackScenarios() {
+	value := os.Getenv("TUPASS_ATTACK_SCENARIOS")
+	if value == "" {
+		return
+	}
+
+	var scenarios []metric.AttackScenario
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			log.Panicf("Could not parse attack scenario %s\n", pair)
+		}
+
+		guessesPerSecond, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || guessesPerSecond <= 0 {
+			log.Panicf("Could not parse guesses per second of attack scenario %s\n", pair)
+		}
+		scenarios = append(scenarios, metric.AttackScenario{Name: strings.TrimSpace(parts[0]), GuessesPerSecond: guessesPerSecond})
+	}
+	metric.AttackScenarios = scenarios
+
+	log.Printf("Setting up attack scenarios done.\n")
+}
+
+// SetupComplexityModel sets metric.ComplexityModel to the version given by environment variable TUPASS_COMPLEXITY_MODEL ("v1" or "v2").
+// If the variable is not set, complexity model v1 is used.
+func SetupComplexityModel() {
+	model := os.Getenv("TUPASS_COMPLEXITY_MODEL")
+	switch model {
+	case "":
+		return
+	case metric.ComplexityModelV1, metric.ComplexityModelV2:
+		metric.ComplexityModel = model
+	default:
+		log.Panicf("Unknown complexity model %s\n", model)
+	}
+
+	log.Printf("Setting up complexity model %s done.\n", model)
+}
+
+// SetupFuzzyModel replaces the TUPass model by the one in the FCL file given by environment variable TUPASS_FUZZY_MODEL
+// (see fes.LoadModel). If the variable is not set, the built-in model is used.
+func SetupFuzzyModel() {
+	model := os.Getenv("TUPASS_FUZZY_MODEL")
+	if model == "" {
+		return
+	}
+
+	if err := fes.LoadModel(model); err != nil {
+		log.Panicf("Could not load fuzzy model %s\n", err)
+	}
+
+	log.Printf("Loading fuzzy model %s done.\n", model)
+}
+
+// SetupInference sets the inference mode of the strength model to the one given by environment variable TUPASS_INFERENCE
+// ("mamdani" or "sugeno", see fes.SetInference). If the variable is not set, Mamdani inference is used.
+func SetupInference() {
+	mode := os.Getenv("TUPASS_INFERENCE")
+	if mode == "" {
+		return
+	}
+
+	if err := fes.SetInference(mode); err != nil {
+		log.Panicf("Could not set up inference %s\n", err)
+	}
+
+	log.Printf("Setting up %s inference done.\n", mode)
+}
+
+// SetupExplainToken sets ExplainToken to the token given by environment variable TUPASS_EXPLAIN_TOKEN, enabling GET /api/explain
+// for requests authorized by it. If the variable is not set, the endpoint is disabled.
+func SetupExplainToken() {
+	token := os.Getenv("TUPASS_EXPLAIN_TOKEN")
+	if token == "" {
+		return
+	}
+	ExplainToken = token
+
+	log.Printf("Setting up explain token done.\n")
+}
@@ -28,6 +28,32 @@ func validateInputLanguage(lg string) bool {
 	return lg == "en" || lg == "de"
 }
 
+// readInput reads the password and language from the headers of the request.
+// If they are not valid, it writes http status 400 without body and returns false.
+func readInput(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+	// get json password from header
+	jsonPassword := r.Header.Get("password")
+	// get language from header
+	language := r.Header.Get("language")
+
+	var password string
+
+	// unquote password
+	errMarshal := json.Unmarshal([]byte(jsonPassword), &password)
+	if errMarshal != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("Error: Could not decode input string")
+		return "", "", false
+
+	} else if !validateInput(password) || !validateInputLanguage(language) {
+		// a bad request simply returns http status 400 without body
+		w.WriteHeader(http.StatusBadRequest)
+		log.Println("Error: Input password or language invalid")
+		return "", "", false
+	}
+	return password, language, true
+}
+
 //RequestHandler takes incoming requests and writes response for cors or for the model calculations length, complexity and predictability
 func RequestHandler(w http.ResponseWriter, r *http.Request) {
 
@@ -48,25 +74,8 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) {
 		log.Println("Processing request...")
 	}
 
-	// get json password from header
-	jsonPassword := r.Header.Get("password")
-	// get language from header
-	language := r.Header.Get("language")
-
-	var password string
-
-	// unquote password
-	errMarshal := json.Unmarshal([]byte(jsonPassword), &password)
-	if errMarshal != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		log.Println("Error: Could not decode input string")
-
-	} else if !validateInput(password) || !validateInputLanguage(language) {
-		// a bad request simply returns http status 400 without body
-		w.WriteHeader(http.StatusBadRequest)
-		log.Println("Error: Input password or language invalid")
-
-	} else {
+	password, language, ok := readInput(w, r)
+	if ok {
 		// return actual response
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/tupass/tupass-backend/api"
+	"github.com/tupass/tupass-backend/fes"
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// configuration is a set of operators of the inference engine.
+type configuration struct {
+	name        string
+	and         fuzzy.TNorm
+	or          fuzzy.SNorm
+	implication fuzzy.TNorm
+	aggregation fuzzy.SNorm
+}
+
+// configurations are the compared operators, the first one is the baseline (the max-min method of the TUPass model).
+var configurations = []configuration{
+	{"min-max", fuzzy.Minimum, fuzzy.Maximum, fuzzy.Minimum, fuzzy.Maximum},
+	{"product", fuzzy.Product, fuzzy.ProbabilisticSum, fuzzy.Product, fuzzy.ProbabilisticSum},
+	{"lukasiewicz", fuzzy.BoundedDifference, fuzzy.BoundedSum, fuzzy.BoundedDifference, fuzzy.BoundedSum},
+	{"hamacher", fuzzy.HamacherProduct, fuzzy.HamacherSum, fuzzy.HamacherProduct, fuzzy.HamacherSum},
+	{"min-sum", fuzzy.Minimum, fuzzy.Maximum, fuzzy.Minimum, fuzzy.Sum},
+	{"product-sum", fuzzy.Product, fuzzy.ProbabilisticSum, fuzzy.Product, fuzzy.Sum}}
+
+// classes are the strength classes of the messages of the API, by their upper bound.
+var classes = []struct {
+	name       string
+	upperBound float64
+}{{"very weak", 20}, {"weak", 40}, {"medium", 60}, {"strong", 80}, {"very strong", 100}}
+
+// class returns the index of the class of the given strength.
+func class(strength float64) int {
+	for i, c := range classes {
+		if math.Round(strength) <= c.upperBound {
+			return i
+		}
+	}
+	return len(classes) - 1
+}
+
+// main compares the strengths of the passwords in a corpus (one per line) by the TUPass model with different
+// operators of the inference engine and writes a report to stdout.
+func main() {
+	language := flag.String("language", "en", "language of the passwords (en or de)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-language en|de] corpus\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// configure the backend like the server does
+	api.SetupPasswordList()
+	api.SetupBreachCorpus()
+	api.SetupComplexityModel()
+	api.SetupLeetTable()
+	api.SetupCostModel()
+	api.SetupFuzzyModel()
+	api.SetupInference()
+
+	grades, err := readCorpus(flag.Arg(0), *language)
+	if err != nil {
+		log.Fatalf("Could not read corpus: %s\n", err)
+	}
+	if len(grades) == 0 {
+		log.Fatalf("Corpus %s is empty\n", flag.Arg(0))
+	}
+	writeReport(os.Stdout, grades)
+}
+
+// readCorpus returns the membership grades of the metrics of all passwords in the given corpus.
+func readCorpus(path, language string) ([]map[string][]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var grades []map[string][]float64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		password := strings.TrimRight(scanner.Text(), "\r")
+		if password == "" {
+			continue
+		}
+		m := api.CalculateMetrics(password, language)
+		grades = append(grades, map[string][]float64{"length": m.LList, "complexity": m.CList, "predictability": m.PList, "breach": m.BList})
+	}
+	return grades, scanner.Err()
+}
+
+// writeReport writes the mean strength, the difference to the baseline, the agreement of the classes with the
+// baseline and the number of passwords per class of every configuration.
+func writeReport(output io.Writer, grades []map[string][]float64) {
+	w := tabwriter.NewWriter(output, 0, 8, 2, ' ', tabwriter.AlignRight)
+	header := "configuration\tAND\tOR\tACT\tACCU\tmean\tmean |Δ|\tmax |Δ|\tsame class\tno rule"
+	for _, c := range classes {
+		header += "\t" + c.name
+	}
+	fmt.Fprintln(w, header+"\t")
+
+	var baseline []float64
+	for _, config := range configurations {
+		engine := fes.Model()
+		engine.And, engine.Or, engine.Implication, engine.Aggregation = config.and, config.or, config.implication, config.aggregation
+
+		strengths := make([]float64, len(grades))
+		counts := make([]int, len(classes))
+		sum, sumDifference, maxDifference, sameClass, noRule := 0.0, 0.0, 0.0, 0, 0
+		for i, g := range grades {
+			strength, err := engine.Evaluate(g)
+			if err == fuzzy.ErrZeroDegree {
+				noRule++
+			}
+			strengths[i] = strength
+			sum += strength
+			counts[class(strength)]++
+
+			if baseline != nil {
+				difference := math.Abs(strength - baseline[i])
+				sumDifference += difference
+				maxDifference = math.Max(maxDifference, difference)
+				if class(strength) == class(baseline[i]) {
+					sameClass++
+				}
+			} else {
+				sameClass++
+			}
+		}
+		if baseline == nil {
+			baseline = strengths
+		}
+
+		n := float64(len(grades))
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%.2f\t%.2f\t%.2f\t%.1f%%\t%d", config.name, config.and, config.or, config.implication, config.aggregation,
+			sum/n, sumDifference/n, maxDifference, 100*float64(sameClass)/n, noRule)
+		for _, count := range counts {
+			line += fmt.Sprintf("\t%d", count)
+		}
+		fmt.Fprintln(w, line+"\t")
+	}
+	w.Flush()
+	fmt.Fprintf(output, "\n%d passwords, differences and classes compared to %s\n", len(grades), configurations[0].name)
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/tupass/tupass-backend/fes"
+)
+
+// main writes the built-in TUPass model in FCL to stdout (see fes/strength.fcl).
+func main() {
+	if err := fes.WriteModel(os.Stdout); err != nil {
+		log.Fatalf("Could not write model: %s\n", err)
+	}
+}
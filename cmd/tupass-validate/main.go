@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tupass/tupass-backend/api"
+	"github.com/tupass/tupass-backend/fes"
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// monotonicity are the expected directions of the strength in the inputs of the TUPass model: it must not decrease
+// with the length and complexity and not increase with the predictability and breach.
+var monotonicity = []struct {
+	input      string
+	decreasing bool
+}{{"length", false}, {"complexity", false}, {"predictability", true}, {"breach", true}}
+
+// maxViolations is the number of monotonicity violations written per input.
+const maxViolations = 10
+
+// main validates the rules of the strength model (the one of TUPASS_FUZZY_MODEL, if configured) and writes a report
+// to stdout. It exits with status 1 if it found any problem.
+func main() {
+	samples := flag.Int("samples", 20, "number of sampled values per input for the monotonicity check")
+	tolerance := flag.Float64("tolerance", 0, "decrease of the strength tolerated by the monotonicity check")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-samples n] [-tolerance t]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// configure the model like the server does
+	api.SetupFuzzyModel()
+	api.SetupInference()
+
+	problems, err := writeReport(os.Stdout, fes.Model(), *samples, *tolerance)
+	if err != nil {
+		log.Fatalf("Could not validate model: %s\n", err)
+	}
+	if problems > 0 {
+		os.Exit(1)
+	}
+}
+
+// writeReport writes the uncovered combinations of terms, overlapping, conflicting and unreachable rules and the
+// violations of the expected monotonicity of the given engine (the largest ones first) and returns the number of problems.
+func writeReport(output io.Writer, e *fuzzy.Engine, samples int, tolerance float64) (int, error) {
+	analysis := e.AnalyzeRules()
+	var names []string
+	for _, input := range e.Inputs {
+		names = append(names, input.Name)
+	}
+	fmt.Fprintf(output, "%d rules, %d combinations of the terms of %s\n", len(e.Rules), analysis.Combinations, strings.Join(names, ", "))
+
+	writeSection(output, "uncovered combinations", len(analysis.Uncovered), func(i int) string {
+		var conditions []string
+		for k, term := range analysis.Uncovered[i] {
+			conditions = append(conditions, e.Inputs[k].Name+" IS "+e.Inputs[k].Terms[term].Name)
+		}
+		return strings.Join(conditions, " AND ")
+	})
+	writeSection(output, "overlapping rules", len(analysis.Overlaps), func(i int) string {
+		first, second := analysis.Overlaps[i][0], analysis.Overlaps[i][1]
+		return fmt.Sprintf("rules %d and %d (%s)", first+1, second+1, e.Rules[first].Then)
+	})
+	writeSection(output, "conflicting rules", len(analysis.Conflicts), func(i int) string {
+		first, second := analysis.Conflicts[i][0], analysis.Conflicts[i][1]
+		return fmt.Sprintf("rules %d and %d (%s and %s)", first+1, second+1, e.Rules[first].Then, e.Rules[second].Then)
+	})
+	writeSection(output, "unreachable rules", len(analysis.Unreachable), func(i int) string {
+		return fmt.Sprintf("rule %d (%s)", analysis.Unreachable[i]+1, e.Rules[analysis.Unreachable[i]].Then)
+	})
+	problems := len(analysis.Uncovered) + len(analysis.Overlaps) + len(analysis.Conflicts) + len(analysis.Unreachable)
+
+	fmt.Fprintf(output, "\nmonotonicity (%d samples per input):\n", samples)
+	for _, m := range monotonicity {
+		violations, err := e.CheckMonotonicity(m.input, m.decreasing, samples, tolerance)
+		if err != nil {
+			return 0, err
+		}
+		sort.SliceStable(violations, func(i, j int) bool {
+			return math.Abs(violations[i].HigherOutput-violations[i].LowerOutput) > math.Abs(violations[j].HigherOutput-violations[j].LowerOutput)
+		})
+		direction := "non-decreasing"
+		if m.decreasing {
+			direction = "non-increasing"
+		}
+		fmt.Fprintf(output, "  %s (%s): %d violations\n", m.input, direction, len(violations))
+		for i, v := range violations {
+			if i == maxViolations {
+				fmt.Fprintf(output, "    ...\n")
+				break
+			}
+			var others []string
+			for _, name := range names {
+				if name != m.input {
+					others = append(others, fmt.Sprintf("%s %.4g", name, v.Lower[name]))
+				}
+			}
+			fmt.Fprintf(output, "    %s %.4g -> %.4g at %s: %s %.2f -> %.2f\n", m.input, v.Lower[m.input], v.Higher[m.input],
+				strings.Join(others, ", "), e.Output.Name, v.LowerOutput, v.HigherOutput)
+		}
+		problems += len(violations)
+	}
+	return problems, nil
+}
+
+// writeSection writes the title of a section of the report with the number of its entries and each entry.
+func writeSection(output io.Writer, title string, count int, entry func(i int) string) {
+	fmt.Fprintf(output, "\n%s: %d\n", title, count)
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(output, "  %s\n", entry(i))
+	}
+}
@@ -1,205 +1,210 @@
 package fes
 
 import (
+	"fmt"
+	"io"
 	"log"
-	"math"
-	"strconv"
-	"strings"
+	"os"
 
 	"github.com/tupass/tupass-backend/fuzzy"
 )
 
-// password strength
-var s = fuzzy.Arrange(0, 100, .1)
-
-// define triangle ranges (Vw=very weak, W=weak, M=medium, S=strong, Vs=very strong)
-var sVwVar, sWVar, sMVar, sSVar, sVsVar = []int{10, 10, 20}, []int{20, 30, 40}, []int{40, 50, 60}, []int{60, 70, 80}, []int{80, 90, 90}
-
-// ruleDict is stores premises (l, c, p) and conclusion (s)
-// l(enght) = (very short, short, medium, long, very long) = (0, 1, 2, 3, 4)
-// c(omplexity) = (very simple, simple, medium, complex, very complex) = (0, 1, 2, 3, 4)
-// p(redictability) = (hard, medium, easy) = (0, 1, 2)
-// s(trength) = (very weak, weak, medium, strong, very strong) = (0, 1, 2, 3, 4)
-var ruleDict = map[string]int{
-	"4,4,0": 4, // 1
-	"4,3,0": 4, // 2
-	"4,2,0": 4, // 3
-	"4,1,0": 3, // 4
-	"4,0,0": 3, // 5
-	"4,4,1": 3, // 6
-	"4,3,1": 3, // 7
-	"4,2,1": 3, // 8
-	"4,1,1": 2, // 9
-	"4,0,1": 2, // 10
-	"4,4,2": 2, // 11
-	"4,3,2": 2, // 12
-	"4,2,2": 2, // 13
-	"4,1,2": 1, // 14
-	"4,0,2": 1, // 15
-	"3,4,0": 4, // 16
-	"3,3,0": 4, // 17
-	"3,2,0": 4, // 18
-	"3,1,0": 3, // 19
-	"3,0,0": 2, // 20
-	"3,4,1": 3, // 21
-	"3,3,1": 3, // 22
-	"3,2,1": 3, // 23
-	"3,1,1": 2, // 24 //changed in V2
-	"3,0,1": 1, // 25
-	"3,*,2": 1, // 26
-	"2,4,0": 3, // 27
-	"2,3,0": 3, // 28
-	"2,2,0": 3, // 29
-	"2,1,0": 2, // 30
-	"2,0,0": 1, // 31
-	"2,4,1": 2, // 32
-	"2,3,1": 2, // 33
-	"2,2,1": 2, // 34 //changed in V2
-	"2,1,1": 2, // 35 //changed in V2
-	"2,0,1": 1, // 36
-	"2,*,2": 1, // 37
-	"1,4,0": 2, // 38
-	"1,3,0": 2, // 39
-	"1,2,0": 2, // 40
-	"1,1,0": 2, // 41 //changed in V2
-	"1,0,0": 0, // 42
-	"1,4,1": 2, // 43 //changed in V2
-	"1,3,1": 2, // 44 //changed in V2
-	"1,2,1": 2, // 45 //changed in V2
-	"1,1,1": 1, // 46 //changed in V2
-	"1,0,1": 0, // 47
-	"1,*,2": 0, // 48
-	"0,*,*": 0} // 49
-
-// updateOutputDict updates the output grade dict for given name of the strength set and
-// Background: if there is only 1 rule can be applied, the membership grade of the output to the set will be the
-// 	result of the rule, but if there are more than one rules, the "maximum of the membership grade" strategy will be
-// 	used  => take the maximum of the results for this set
-//     @param value: the result of the considering rule
-func updateOutputDict(setName int, value float64, outputGradesDict *[5]float64) {
-
-	//calculate and append output grades
-	if outputGradesDict[setName] != 0.0 {
-		//if there are more than one rules which can be applied, take the maximum result
-		(*outputGradesDict)[setName] = math.Max(value, outputGradesDict[setName])
-	} else {
-		//if there is still no result for this set
-		(*outputGradesDict)[setName] = value
-	}
+// StrengthVariable is the linguistic variable of the password strength (very weak, weak, medium, strong, very strong).
+var StrengthVariable = fuzzy.Variable{
+	Name:     "strength",
+	Universe: fuzzy.Arrange(0, 100, .1),
+	Terms: []fuzzy.Term{
+		{Name: "veryWeak", MF: fuzzy.Triangle{Left: 10, Middle: 10, Right: 20}},
+		{Name: "weak", MF: fuzzy.Triangle{Left: 20, Middle: 30, Right: 40}},
+		{Name: "medium", MF: fuzzy.Triangle{Left: 40, Middle: 50, Right: 60}},
+		{Name: "strong", MF: fuzzy.Triangle{Left: 60, Middle: 70, Right: 80}},
+		{Name: "veryStrong", MF: fuzzy.Triangle{Left: 80, Middle: 90, Right: 90}}}}
+
+// rule returns the rule concluding strength s from length l, complexity c and predictability p (any of them may be
+// fuzzy.Any). Like all rules but rule 50, it additionally requires the password to be not breached, so a breached
+// password is always very weak.
+func rule(l, c, p, s string) fuzzy.Rule {
+	return fuzzy.Rule{
+		If: fuzzy.And{
+			fuzzy.Is{Variable: "length", Term: l},
+			fuzzy.Is{Variable: "complexity", Term: c},
+			fuzzy.Is{Variable: "predictability", Term: p},
+			fuzzy.Is{Variable: "breach", Term: "notBreached"}},
+		Then: s}
 }
 
-// Calculation for each rule in inference engine
-// pos1: position for length index
-// pos2: position for complexity index
-// pos3: position for predictability index
-// gradeL: membership grade of length input
-// gradeC: membership grade of complexity input
-// gradeP: membership grade of predictability input
-func calculateForEachRule(pos1, pos2, pos3 string, gradeL, gradeC, gradeP float64, outputGradesDict *[5]float64) {
-	//rule = '0,*,*'
-	rule := strings.Join([]string{pos1, pos2, pos3}, ",")
-	//get the name of the strength set
-	strengthSet := ruleDict[rule]
-
-	//calculate the grade result of output for the rule
-	if gradeL == -1 {
-		print("No rules with L = * in the rule set")
-	} else {
-		if gradeC == -1 { //Rule 26, 37, 48, 49
-			if gradeP == -1 { //Rule 49
-				ruleResult := gradeL
-				updateOutputDict(strengthSet, ruleResult, outputGradesDict)
-			} else { //Rule 26, 37, 48
-				ruleResult := math.Min(gradeL, gradeP)
-				updateOutputDict(strengthSet, ruleResult, outputGradesDict)
-			}
-		} else {
-			if gradeP == -1 {
-				log.Printf("No rules with L=%s and C=%s but P = *", pos1, pos2)
-			} else { //Other normal rules
-				ruleResult := math.Min(gradeL, math.Min(gradeC, gradeP))
-				updateOutputDict(strengthSet, ruleResult, outputGradesDict)
-			}
-		}
+// Rules are the rules of the TUPass model, concluding s(trength) from l(ength), c(omplexity), p(redictability) and b(reach).
+var Rules = []fuzzy.Rule{
+	rule("veryLong", "veryComplex", "hard", "veryStrong"), // 1
+	rule("veryLong", "complex", "hard", "veryStrong"),     // 2
+	rule("veryLong", "medium", "hard", "veryStrong"),      // 3
+	rule("veryLong", "simple", "hard", "strong"),          // 4
+	rule("veryLong", "verySimple", "hard", "strong"),      // 5
+	rule("veryLong", "veryComplex", "medium", "strong"),   // 6
+	rule("veryLong", "complex", "medium", "strong"),       // 7
+	rule("veryLong", "medium", "medium", "strong"),        // 8
+	rule("veryLong", "simple", "medium", "medium"),        // 9
+	rule("veryLong", "verySimple", "medium", "medium"),    // 10
+	rule("veryLong", "veryComplex", "easy", "medium"),     // 11
+	rule("veryLong", "complex", "easy", "medium"),         // 12
+	rule("veryLong", "medium", "easy", "medium"),          // 13
+	rule("veryLong", "simple", "easy", "weak"),            // 14
+	rule("veryLong", "verySimple", "easy", "weak"),        // 15
+	rule("long", "veryComplex", "hard", "veryStrong"),     // 16
+	rule("long", "complex", "hard", "veryStrong"),         // 17
+	rule("long", "medium", "hard", "veryStrong"),          // 18
+	rule("long", "simple", "hard", "strong"),              // 19
+	rule("long", "verySimple", "hard", "medium"),          // 20
+	rule("long", "veryComplex", "medium", "strong"),       // 21
+	rule("long", "complex", "medium", "strong"),           // 22
+	rule("long", "medium", "medium", "strong"),            // 23
+	rule("long", "simple", "medium", "medium"),            // 24 //changed in V2
+	rule("long", "verySimple", "medium", "weak"),          // 25
+	rule("long", fuzzy.Any, "easy", "weak"),               // 26
+	rule("medium", "veryComplex", "hard", "strong"),       // 27
+	rule("medium", "complex", "hard", "strong"),           // 28
+	rule("medium", "medium", "hard", "strong"),            // 29
+	rule("medium", "simple", "hard", "medium"),            // 30
+	rule("medium", "verySimple", "hard", "weak"),          // 31
+	rule("medium", "veryComplex", "medium", "medium"),     // 32
+	rule("medium", "complex", "medium", "medium"),         // 33
+	rule("medium", "medium", "medium", "medium"),          // 34 //changed in V2
+	rule("medium", "simple", "medium", "medium"),          // 35 //changed in V2
+	rule("medium", "verySimple", "medium", "weak"),        // 36
+	rule("medium", fuzzy.Any, "easy", "weak"),             // 37
+	rule("short", "veryComplex", "hard", "medium"),        // 38
+	rule("short", "complex", "hard", "medium"),            // 39
+	rule("short", "medium", "hard", "medium"),             // 40
+	rule("short", "simple", "hard", "medium"),             // 41 //changed in V2
+	rule("short", "verySimple", "hard", "veryWeak"),       // 42
+	rule("short", "veryComplex", "medium", "medium"),      // 43 //changed in V2
+	rule("short", "complex", "medium", "medium"),          // 44 //changed in V2
+	rule("short", "medium", "medium", "medium"),           // 45 //changed in V2
+	rule("short", "simple", "medium", "weak"),             // 46 //changed in V2
+	rule("short", "verySimple", "medium", "veryWeak"),     // 47
+	rule("short", fuzzy.Any, "easy", "veryWeak"),          // 48
+	rule("veryShort", fuzzy.Any, fuzzy.Any, "veryWeak"),   // 49
+	// 50: breached passwords are very weak
+	{If: fuzzy.Is{Variable: "breach", Term: "breached"}, Then: "veryWeak"}}
+
+// builtInEngine is the inference engine of the TUPass model. The complexity variable only provides the names of the
+// terms, the membership grades of both complexity models share them.
+var builtInEngine = func() *fuzzy.Engine {
+	inputs := []fuzzy.Variable{fuzzy.LengthVariable, fuzzy.ComplexityVariable, fuzzy.PredictabilityVariable, fuzzy.BreachVariable}
+	e, err := fuzzy.NewEngine(inputs, StrengthVariable, Rules)
+	if err != nil {
+		log.Panicf("Invalid TUPass model: %s\n", err)
 	}
-}
+	return e
+}()
 
-// GetStrengthByMembershipGrades returns the total strength based on given membership grades for length, complexity and predicatbility
-func GetStrengthByMembershipGrades(LList, CList, PList []float64) float64 {
-	var outputGradesDict = [5]float64{0.0, 0.0, 0.0, 0.0, 0.0}
-	for indexL, itemL := range LList {
-		if itemL == 0 {
-			continue
-		} else {
-			for indexC, itemC := range CList {
-				if itemC == 0 {
-					continue
-				} else {
-					for indexP, itemP := range PList {
-						if itemP == 0 {
-							continue
-						} else {
-							if indexL == 0 { //Rule 49
-								calculateForEachRule(strconv.Itoa(indexL), "*", "*", itemL, -1, -1, &outputGradesDict)
-							} else if (indexL == 1) && (indexP == 2) { //Rule 48
-								//rule = '1,*,2'
-								calculateForEachRule(strconv.Itoa(indexL), "*", strconv.Itoa(indexP), itemL, -1, itemP, &outputGradesDict)
-							} else if (indexL == 2) && (indexP == 2) { //Rule 37
-								//rule = '2,*,2'
-								calculateForEachRule(strconv.Itoa(indexL), "*", strconv.Itoa(indexP), itemL, -1, itemP, &outputGradesDict)
-							} else if (indexL == 3) && (indexP == 2) { //Rule 26
-								//rule = '3,*,2'
-								calculateForEachRule(strconv.Itoa(indexL), "*", strconv.Itoa(indexP), itemL, -1, itemP, &outputGradesDict)
-							} else { //other rules
-								calculateForEachRule(strconv.Itoa(indexL), strconv.Itoa(indexC), strconv.Itoa(indexP), itemL, itemC, itemP, &outputGradesDict)
-							}
-						}
-					}
-				}
-			}
-		}
-	}
+// Inference modes of SetInference.
+const (
+	Mamdani = "mamdani"
+	Sugeno  = "sugeno"
+)
 
-	//output membership functions:
-	sVw, _ := fuzzy.DetTriangleMF(s, sVwVar)
-	sW, _ := fuzzy.DetTriangleMF(s, sWVar)
-	sM, _ := fuzzy.DetTriangleMF(s, sMVar)
-	sS, _ := fuzzy.DetTriangleMF(s, sSVar)
-	sVs, _ := fuzzy.DetTriangleMF(s, sVsVar)
+// model is the TUPass model, the built-in one unless a model was loaded by LoadModel.
+var model = builtInEngine
 
-	//find fuzzy output data (area) : inference method: Max-min method
-	set0 := fMin(outputGradesDict[0], sVw)
-	set1 := fMin(outputGradesDict[1], sW)
-	set2 := fMin(outputGradesDict[2], sM)
-	set3 := fMin(outputGradesDict[3], sS)
-	set4 := fMin(outputGradesDict[4], sVs)
+// inference is the inference mode set by SetInference.
+var inference = Mamdani
 
-	//Aggregate all output - Max of Min
-	outputArea := fMax(set0, fMax(set1, fMax(set2, fMax(set3, set4))))
+// engine is the inference engine in use, the model in the inference mode.
+var engine = model
 
-	//Defuzzification using CoG
-	strength := fuzzy.Defuzzy(s, outputArea)
+// modelTerms are the number of terms of each input variable a loaded model must have,
+// as hints and results refer to the terms of the TUPass model by their index.
+var modelTerms = map[string]int{"length": 5, "complexity": 5, "predictability": 3, "breach": 2}
 
-	return strength
-}
+// LoadModel replaces the TUPass model by the one in the given FCL file (see fuzzy.ParseFCL). The model must have the
+// input variables length, complexity, predictability and breach with as many terms as those of the TUPass model.
+func LoadModel(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
 
-//fMax compares two arrays and returns a new array containing the element-wise maxima.
-func fMax(a, b []float64) []float64 {
-	fuzzy.PanicIfUnequalLength(a, b, "a", "b")
-	for i, val := range b {
-		if a[i] < val {
-			a[i] = val
+	e, err := fuzzy.ParseFCL(file)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	if len(e.Inputs) != len(modelTerms) {
+		return fmt.Errorf("%s: expected the input variables length, complexity, predictability and breach", path)
+	}
+	for _, input := range e.Inputs {
+		terms, ok := modelTerms[input.Name]
+		if !ok {
+			return fmt.Errorf("%s: unknown input variable %s", path, input.Name)
+		}
+		if len(input.Terms) != terms {
+			return fmt.Errorf("%s: expected %d terms of variable %s, but found %d", path, terms, input.Name, len(input.Terms))
 		}
 	}
-	return a
+	model = e
+	return SetInference(inference)
+}
+
+// IsModelLoaded returns whether a model was loaded by LoadModel, whose membership functions must be used (see Fuzzify).
+func IsModelLoaded() bool {
+	return model != builtInEngine
 }
 
-//fMin compares two arrays and returns a new array containing the element-wise minima.
-func fMin(a float64, b []float64) []float64 {
-	for i, val := range b {
-		if a < val {
-			b[i] = a
+// SetInference sets the inference mode, Mamdani (the default) or Sugeno. In Sugeno mode, the strength is the average
+// of constant strengths of the rules weighted by their activations, which are derived from the output terms of the
+// model (see fuzzy.Engine.DeriveConsequents). A Sugeno model loaded by LoadModel is always used in Sugeno mode.
+func SetInference(mode string) error {
+	switch mode {
+	case Mamdani:
+		engine = model
+	case Sugeno:
+		if model.IsSugeno() {
+			engine = model
+			break
+		}
+		consequents, err := model.DeriveConsequents()
+		if err != nil {
+			return err
 		}
+		e := *model
+		e.Consequents = consequents
+		engine = &e
+	default:
+		return fmt.Errorf("unknown inference mode %s", mode)
 	}
-	return b
+	inference = mode
+	return nil
+}
+
+// Fuzzify returns the membership grades for length, complexity, predictability and breach by the membership
+// functions of the model in use.
+func Fuzzify(length, complexity, predictability, breachCount float64) (LList, CList, PList, BList []float64) {
+	grades := map[string][]float64{}
+	values := map[string]float64{"length": length, "complexity": complexity, "predictability": predictability, "breach": breachCount}
+	for _, input := range engine.Inputs {
+		grades[input.Name] = input.Fuzzify(values[input.Name])
+	}
+	return grades["length"], grades["complexity"], grades["predictability"], grades["breach"]
+}
+
+// Model returns a copy of the inference engine in use, e.g. to compare its operators (see fuzzy.Engine).
+func Model() *fuzzy.Engine {
+	e := *engine
+	return &e
+}
+
+// WriteModel writes the built-in TUPass model in FCL, e.g. as a template for a model loaded by LoadModel.
+func WriteModel(writer io.Writer) error {
+	return fuzzy.WriteFCL(writer, builtInEngine, "tupass")
+}
+
+// GetStrengthByMembershipGrades returns the total strength based on given membership grades for length, complexity, predicatbility and breach
+func GetStrengthByMembershipGrades(LList, CList, PList, BList []float64) float64 {
+	strength, err := engine.Evaluate(map[string][]float64{"length": LList, "complexity": CList, "predictability": PList, "breach": BList})
+	if err != nil {
+		// no rule is activated (only possible with a loaded model), strength is the default value of the model
+		log.Printf("Could not infer strength: %s\n", err)
+	}
+	return strength
 }
@@ -1,16 +1,47 @@
 package fuzzy
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"math"
+	"sort"
 )
 
+// DefuzzificationMethod is a method turning the aggregated membership function of an output into a crisp value,
+// named as in the Fuzzy Control Language.
+type DefuzzificationMethod string
+
+const (
+	// Centroid is the centre of gravity of the area under the membership function (the default).
+	Centroid DefuzzificationMethod = "COG"
+	// Bisector is the value dividing the area under the membership function into two halves of equal size.
+	Bisector DefuzzificationMethod = "COA"
+	// MeanOfMaximum is the mean of the values with maximum membership grade.
+	MeanOfMaximum DefuzzificationMethod = "MM"
+	// SmallestOfMaximum is the smallest value with maximum membership grade.
+	SmallestOfMaximum DefuzzificationMethod = "LM"
+	// LargestOfMaximum is the largest value with maximum membership grade.
+	LargestOfMaximum DefuzzificationMethod = "RM"
+	// WeightedAverage is the average of the centres of the output terms weighted by their membership grades
+	// (see DefuzzyWeightedAverage), it does not use the aggregated membership function.
+	WeightedAverage DefuzzificationMethod = "COGS"
+)
+
+// DefuzzificationMethods are all defuzzification methods.
+var DefuzzificationMethods = []DefuzzificationMethod{Centroid, Bisector, MeanOfMaximum, SmallestOfMaximum, LargestOfMaximum, WeightedAverage}
+
+// ErrZeroDegree is returned when defuzzifying a membership function or membership grades of zero degree,
+// e.g. because no rule is activated.
+var ErrZeroDegree = errors.New("membership function has zero degree")
+
 // Defuzzy returns the defuzzified value of given YArray (MF curve of Y (triangles))
 // and YMFG (MF grade of Y determined using rules (the areas)) as float64.
 // YArray and YMFG must be of same length, otherwise Defuzzy panics.
-// If YMFG has zero degree (so the sum of all entries equal zero), Defuzzy panics.
-// Defuzzy uses centroid function to calculate crisp output data.
-func Defuzzy(YArray []float64, YMFG []float64) float64 {
+// If YMFG has zero degree (so the sum of all entries equal zero), Defuzzy returns 0 and ErrZeroDegree.
+// Defuzzy uses the given method (the centroid if empty) to calculate crisp output data, except WeightedAverage,
+// which needs the grades of the output terms (see DefuzzyWeightedAverage).
+func Defuzzy(YArray []float64, YMFG []float64, method DefuzzificationMethod) (float64, error) {
 	PanicIfUnequalLength(YArray, YMFG, "YArray", "YMFG")
 
 	sum := 0.0
@@ -20,10 +51,98 @@ func Defuzzy(YArray []float64, YMFG []float64) float64 {
 
 	zeroDegree := sum == 0
 	if zeroDegree {
-		log.Panicln("Error while trying to defuzzy: YMFG has zero degree")
+		return 0, ErrZeroDegree
+	}
+
+	switch method {
+	case Centroid, "":
+		return centroid(YArray, YMFG), nil
+	case Bisector:
+		return bisector(YArray, YMFG), nil
+	case MeanOfMaximum, SmallestOfMaximum, LargestOfMaximum:
+		smallest, mean, largest := maxima(YArray, YMFG)
+		if method == SmallestOfMaximum {
+			return smallest, nil
+		} else if method == LargestOfMaximum {
+			return largest, nil
+		}
+		return mean, nil
 	}
+	return 0, fmt.Errorf("defuzzification method %s is not supported by Defuzzy", method)
+}
+
+// DefuzzyWeightedAverage returns the average of given centres of the output terms weighted by their membership grades.
+// If the grades have zero degree, DefuzzyWeightedAverage returns 0 and ErrZeroDegree.
+func DefuzzyWeightedAverage(centres []float64, grades []float64) (float64, error) {
+	PanicIfUnequalLength(centres, grades, "centres", "grades")
+
+	sum, weightedSum := 0.0, 0.0
+	for i, grade := range grades {
+		sum += grade
+		weightedSum += grade * centres[i]
+	}
+	if sum == 0 {
+		return 0, ErrZeroDegree
+	}
+	return weightedSum / sum, nil
+}
 
-	return centroid(YArray, YMFG)
+// bisector returns the value dividing the area under the MF grades YMFG of YArray, linearly interpolated
+// between the data points, into two halves.
+func bisector(YArray []float64, YMFG []float64) float64 {
+	if len(YArray) == 1 {
+		return YArray[0]
+	}
+
+	// areas of the trapezoids between the data points
+	areas := make([]float64, len(YArray)-1)
+	total := 0.0
+	for i := 1; i < len(YArray); i++ {
+		areas[i-1] = 0.5 * (YArray[i] - YArray[i-1]) * (YMFG[i-1] + YMFG[i])
+		total += areas[i-1]
+	}
+	if total == 0 {
+		// all grades are on isolated points (or the universe is a single value)
+		_, mean, _ := maxima(YArray, YMFG)
+		return mean
+	}
+
+	half := total / 2
+	for i, area := range areas {
+		if area < half || area == 0 {
+			half -= area
+			continue
+		}
+
+		// solve the area from x1 to x1+t·(x2-x1) under the line from y1 to y2 for the remaining half:
+		// (x2-x1)·(y1·t + (y2-y1)·t²/2) = half
+		x1, x2, y1, y2 := YArray[i], YArray[i+1], YMFG[i], YMFG[i+1]
+		a, b, c := (y2-y1)/2, y1, -half/(x2-x1)
+		t := -c / b
+		if a != 0 {
+			t = (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+		}
+		return x1 + math.Max(0, math.Min(1, t))*(x2-x1)
+	}
+	return YArray[len(YArray)-1]
+}
+
+// maxima returns the smallest, mean and largest value of YArray with maximum MF grade in YMFG.
+func maxima(YArray []float64, YMFG []float64) (smallest, mean, largest float64) {
+	maximum := math.Inf(-1)
+	count := 0.0
+	sum := 0.0
+	for i, y := range YMFG {
+		if y > maximum {
+			maximum, smallest, count, sum = y, YArray[i], 0, 0
+		}
+		if y == maximum {
+			largest = YArray[i]
+			count++
+			sum += YArray[i]
+		}
+	}
+	return smallest, sum / count, largest
 }
 
 // centroid calculates the crisp output data and centroids for given YArray (MF curve of Y)
@@ -71,6 +190,128 @@ func centroid(YArray []float64, YMFG []float64) float64 {
 	return StepSquare / math.Max(TotalSquare, epsilon)
 }
 
+// outputCorners returns the sorted corners of the membership functions of the given output within its universe and
+// the bounds of the universe, between which the membership functions are linear, or nil if they are not piecewise
+// linear (triangles and trapezoids).
+func outputCorners(output Variable) []float64 {
+	if len(output.Universe) < 2 {
+		return nil
+	}
+	lower, upper := output.Universe[0], output.Universe[len(output.Universe)-1]
+	corners := []float64{lower, upper}
+	for _, term := range output.Terms {
+		var termCorners []float64
+		switch mf := term.MF.(type) {
+		case Triangle:
+			termCorners = []float64{mf.Left, mf.Middle, mf.Right}
+		case Trapezoid:
+			termCorners = []float64{mf.Left, mf.LeftTop, mf.RightTop, mf.Right}
+		default:
+			return nil
+		}
+		for _, corner := range termCorners {
+			if lower < corner && corner < upper {
+				corners = append(corners, corner)
+			}
+		}
+	}
+	sort.Float64s(corners)
+	return corners
+}
+
+// isPiecewiseLinear returns whether the aggregated membership function of the output is piecewise linear:
+// its membership functions are, and so are the implication (MIN, PROD or BDIF) and aggregation (MAX, SUM or BSUM).
+func (e *Engine) isPiecewiseLinear() bool {
+	switch e.Implication {
+	case Minimum, Product, BoundedDifference, "":
+	default:
+		return false
+	}
+	switch e.Aggregation {
+	case Maximum, Sum, BoundedSum, "":
+	default:
+		return false
+	}
+	return e.corners != nil
+}
+
+// implied returns the membership grade of the given value to output term i implied by the given grade.
+func (e *Engine) implied(i int, grade, x float64) float64 {
+	return e.Implication.Apply(grade, e.Output.Terms[i].MF.Grade(x))
+}
+
+// exactCentroid returns the centroid of the output membership functions implied by the given grades and aggregated,
+// which must be piecewise linear (see isPiecewiseLinear). Instead of sampling them on the universe, it calculates the
+// breakpoints of the aggregated membership function, between which it is linear, so the centroid is exact.
+// If it has zero degree, exactCentroid returns 0 and ErrZeroDegree.
+func (e *Engine) exactCentroid(outputGrades []float64) (float64, error) {
+	// the implied membership functions are linear between the corners and where the implication clips them
+	// (MIN at the grade, BDIF at one minus the grade)
+	var buffer [128]float64
+	breakpoints := append(buffer[:0], e.corners...)
+	if e.Implication != Product {
+		for k := len(e.corners) - 1; k > 0; k-- {
+			a, b := e.corners[k-1], e.corners[k]
+			for i, term := range e.Output.Terms {
+				level := outputGrades[i]
+				if e.Implication == BoundedDifference {
+					level = 1 - level
+				}
+				breakpoints = appendCrossing(breakpoints, a, b, term.MF.Grade(a)-level, term.MF.Grade(b)-level)
+			}
+		}
+		sort.Float64s(breakpoints)
+	}
+
+	// the aggregated membership function is linear between those breakpoints and where the implied membership
+	// functions cross each other (MAX) or their sum crosses 1 (BSUM)
+	if e.Aggregation != Sum {
+		for k := len(breakpoints) - 1; k > 0; k-- {
+			a, b := breakpoints[k-1], breakpoints[k]
+			sumA, sumB := 0.0, 0.0
+			for i, grade := range outputGrades {
+				impliedA, impliedB := e.implied(i, grade, a), e.implied(i, grade, b)
+				sumA, sumB = sumA+impliedA, sumB+impliedB
+				if e.Aggregation == BoundedSum {
+					continue
+				}
+				for j := i + 1; j < len(outputGrades); j++ {
+					breakpoints = appendCrossing(breakpoints, a, b, impliedA-e.implied(j, outputGrades[j], a), impliedB-e.implied(j, outputGrades[j], b))
+				}
+			}
+			if e.Aggregation == BoundedSum {
+				breakpoints = appendCrossing(breakpoints, a, b, sumA-1, sumB-1)
+			}
+		}
+		sort.Float64s(breakpoints)
+	}
+
+	var valuesBuffer [128]float64
+	values := valuesBuffer[:0]
+	zeroDegree := true
+	for _, x := range breakpoints {
+		value := 0.0
+		for i, grade := range outputGrades {
+			value = e.Aggregation.Apply(value, e.implied(i, grade, x))
+		}
+		values = append(values, value)
+		zeroDegree = zeroDegree && value == 0
+	}
+	if zeroDegree {
+		return 0, ErrZeroDegree
+	}
+	return centroid(breakpoints, values), nil
+}
+
+// appendCrossing appends the value between a and b at which a linear function crosses zero to breakpoints,
+// given its values at a and b, if it changes its sign.
+func appendCrossing(breakpoints []float64, a, b, valueA, valueB float64) []float64 {
+	if valueA < 0 && valueB > 0 || valueA > 0 && valueB < 0 {
+		breakpoints = append(breakpoints, a+(b-a)*valueA/(valueA-valueB))
+	}
+	return breakpoints
+}
+
 // PanicIfUnequalLength panics if given arrays x and y are of unequal length.
 // xName and yName should describe array x and y for debug purposes.
 func PanicIfUnequalLength(x, y []float64, xName, yName string) {
@@ -0,0 +1,338 @@
+package fuzzy
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// Any is the term of a condition matching every term of its variable (the wildcard "*" of a rule).
+const Any = "*"
+
+// Term is a linguistic term of a variable with its membership function.
+type Term struct {
+	Name string
+	MF   MembershipFunction
+}
+
+// Variable is a linguistic variable with the discrete universe its membership functions are sampled on
+// (for defuzzification) and its terms. The membership grades of a value are given in the order of the terms.
+type Variable struct {
+	Name     string
+	Universe []float64
+	Terms    []Term
+}
+
+// TermIndex returns the index of the term with the given name, or -1 if the variable has no such term.
+func (v Variable) TermIndex(name string) int {
+	for i, term := range v.Terms {
+		if term.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// MembershipFunctions returns the membership functions of the terms evaluated on the universe.
+func (v Variable) MembershipFunctions() ([][]float64, error) {
+	mfs := make([][]float64, len(v.Terms))
+	for i, term := range v.Terms {
+		mf, err := DetMF(v.Universe, term.MF)
+		if err != nil {
+			return nil, fmt.Errorf("term %s of variable %s: %v", term.Name, v.Name, err)
+		}
+		mfs[i] = mf
+	}
+	return mfs, nil
+}
+
+// Fuzzify returns the membership grades of the given value to the terms of the variable (see AppendGrades).
+func (v Variable) Fuzzify(value float64) []float64 {
+	return v.AppendGrades(make([]float64, 0, len(v.Terms)), value)
+}
+
+// AppendGrades appends the membership grades of the given value to the terms of the variable to grades and returns
+// the extended slice, so it does not allocate if grades has enough capacity.
+// The membership functions are evaluated analytically at the value limited to the universe, like DetMFGrad does on
+// membership functions sampled on the universe, and are not validated (see MembershipFunctions).
+func (v Variable) AppendGrades(grades []float64, value float64) []float64 {
+	if n := len(v.Universe); n > 0 {
+		value = math.Max(v.Universe[0], math.Min(value, v.Universe[n-1]))
+	}
+	for _, term := range v.Terms {
+		grades = append(grades, term.MF.Grade(value))
+	}
+	return grades
+}
+
+// Antecedent is the premise of a rule: a condition (Is) or a combination of antecedents (Not, And, Or).
+type Antecedent interface {
+	// compile returns the activation of the antecedent for the given input variables,
+	// or an error if it refers to unknown variables or terms.
+	compile(inputs map[string]Variable) (activation, error)
+}
+
+// activation returns the degree to which an antecedent is fulfilled by the membership grades of the input variables
+// (by name, in the order of their terms), using the operators of the given engine.
+type activation func(e *Engine, grades map[string][]float64) float64
+
+// Is is the condition "Variable IS Hedges Term". If Term is Any, it is always fulfilled.
+// The hedges modify the membership grade of the term from right to left, e.g. "very somewhat" squares its root.
+type Is struct {
+	Variable string
+	Term     string
+	Hedges   []Hedge
+}
+
+func (is Is) compile(inputs map[string]Variable) (activation, error) {
+	variable, ok := inputs[is.Variable]
+	if !ok {
+		return nil, fmt.Errorf("unknown input variable %s", is.Variable)
+	}
+	for _, hedge := range is.Hedges {
+		if _, ok := isHedge(string(hedge)); !ok {
+			return nil, fmt.Errorf("unknown hedge %s", hedge)
+		}
+	}
+	if is.Term == Any {
+		if len(is.Hedges) > 0 {
+			return nil, fmt.Errorf("hedges of the wildcard of variable %s", is.Variable)
+		}
+		return func(*Engine, map[string][]float64) float64 { return 1 }, nil
+	}
+
+	index := variable.TermIndex(is.Term)
+	if index < 0 {
+		return nil, fmt.Errorf("unknown term %s of variable %s", is.Term, is.Variable)
+	}
+	if len(is.Hedges) == 0 {
+		return func(_ *Engine, grades map[string][]float64) float64 { return grades[is.Variable][index] }, nil
+	}
+	return func(_ *Engine, grades map[string][]float64) float64 {
+		grade := grades[is.Variable][index]
+		for i := len(is.Hedges) - 1; i >= 0; i-- {
+			grade = is.Hedges[i].Apply(grade)
+		}
+		return grade
+	}, nil
+}
+
+// Not is the negation of an antecedent (1 minus its activation).
+type Not struct {
+	Antecedent Antecedent
+}
+
+func (not Not) compile(inputs map[string]Variable) (activation, error) {
+	negated, err := not.Antecedent.compile(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return func(e *Engine, grades map[string][]float64) float64 { return 1 - negated(e, grades) }, nil
+}
+
+// And is the conjunction of antecedents (t-norm And of the engine of their activations).
+type And []Antecedent
+
+func (and And) compile(inputs map[string]Variable) (activation, error) {
+	activations, err := compileAll(and, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return func(e *Engine, grades map[string][]float64) float64 {
+		result := 1.0
+		for _, a := range activations {
+			result = e.And.Apply(result, a(e, grades))
+		}
+		return result
+	}, nil
+}
+
+// Or is the disjunction of antecedents (s-norm Or of the engine of their activations).
+type Or []Antecedent
+
+func (or Or) compile(inputs map[string]Variable) (activation, error) {
+	activations, err := compileAll(or, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return func(e *Engine, grades map[string][]float64) float64 {
+		result := 0.0
+		for _, a := range activations {
+			result = e.Or.Apply(result, a(e, grades))
+		}
+		return result
+	}, nil
+}
+
+// compileAll compiles all given antecedents.
+func compileAll(antecedents []Antecedent, inputs map[string]Variable) ([]activation, error) {
+	activations := make([]activation, len(antecedents))
+	for i, antecedent := range antecedents {
+		a, err := antecedent.compile(inputs)
+		if err != nil {
+			return nil, err
+		}
+		activations[i] = a
+	}
+	return activations, nil
+}
+
+// Rule is a fuzzy rule "IF If THEN output IS Then WITH Weight".
+type Rule struct {
+	If   Antecedent
+	Then string
+	// Weight is the certainty factor in (0, 1] the activation of the rule is multiplied by, 1 if zero.
+	Weight float64
+}
+
+// Engine is a Mamdani fuzzy inference engine with any number of input variables and one output variable.
+// Rules are activated by the t-norm And and the s-norm Or of membership grades, the activations of rules with the
+// same conclusion are combined by the s-norm Aggregation, output membership functions are implied by the t-norm
+// Implication of their activation, aggregated by Aggregation and defuzzified by Method. By default, these are the
+// minimum, maximum, minimum (clipping), maximum and the centroid (the max-min method).
+// With Consequents, the engine is in (Takagi-)Sugeno mode instead (see Sugeno).
+type Engine struct {
+	Inputs []Variable
+	Output Variable
+	Rules  []Rule
+	// And, Or, Implication and Aggregation are the operators of the inference, min or max if empty.
+	And         TNorm
+	Or          SNorm
+	Implication TNorm
+	Aggregation SNorm
+	// Method is the defuzzification method, the centroid if empty.
+	Method DefuzzificationMethod
+	// Default is the output value if no rule is activated.
+	Default float64
+	// Consequents are the crisp consequents of the output terms (by name) in Sugeno mode, nil in Mamdani mode.
+	Consequents map[string]Consequent
+
+	inputs      map[string]Variable
+	activations []activation
+	conclusions []int
+	weights     []float64
+	outputMFs   [][]float64
+	centres     []float64
+	corners     []float64
+}
+
+// NewEngine returns an engine for the given variables and rules.
+// It returns an error if a rule refers to an unknown variable or term or a membership function is invalid.
+func NewEngine(inputs []Variable, output Variable, rules []Rule) (*Engine, error) {
+	e := &Engine{Inputs: inputs, Output: output, Rules: rules, inputs: map[string]Variable{}}
+	for _, input := range inputs {
+		if _, ok := e.inputs[input.Name]; ok {
+			return nil, fmt.Errorf("duplicate input variable %s", input.Name)
+		}
+		if _, err := input.MembershipFunctions(); err != nil {
+			return nil, err
+		}
+		e.inputs[input.Name] = input
+	}
+
+	for i, rule := range rules {
+		if rule.If == nil {
+			return nil, fmt.Errorf("rule %d: missing antecedent", i+1)
+		}
+		a, err := rule.If.compile(e.inputs)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %v", i+1, err)
+		}
+		e.activations = append(e.activations, a)
+		conclusion := output.TermIndex(rule.Then)
+		if conclusion < 0 {
+			return nil, fmt.Errorf("rule %d: unknown term %s of variable %s", i+1, rule.Then, output.Name)
+		}
+		e.conclusions = append(e.conclusions, conclusion)
+
+		weight := rule.Weight
+		if weight == 0 {
+			weight = 1
+		} else if !(0 < weight && weight <= 1) {
+			return nil, fmt.Errorf("rule %d: weight %v is not in (0, 1]", i+1, rule.Weight)
+		}
+		e.weights = append(e.weights, weight)
+	}
+
+	outputMFs, err := output.MembershipFunctions()
+	if err != nil {
+		return nil, err
+	}
+	e.outputMFs = outputMFs
+
+	// the centre of an output term (for WeightedAverage) is the mean of its maxima
+	for _, mf := range outputMFs {
+		_, centre, _ := maxima(output.Universe, mf)
+		e.centres = append(e.centres, centre)
+	}
+	e.corners = outputCorners(output)
+	return e, nil
+}
+
+// Infer returns the membership grades of the output to its terms for the given membership grades of the inputs
+// (by name, in the order of their terms): the weighted activations of all rules concluding each term combined by
+// Aggregation.
+func (e *Engine) Infer(grades map[string][]float64) []float64 {
+	for name, input := range e.inputs {
+		if len(grades[name]) != len(input.Terms) {
+			log.Panicf("Expected %d membership grades of input variable %s, but found %d instead.", len(input.Terms), name, len(grades[name]))
+		}
+	}
+
+	outputGrades := make([]float64, len(e.Output.Terms))
+	for i, a := range e.activations {
+		conclusion := e.conclusions[i]
+		outputGrades[conclusion] = e.Aggregation.Apply(outputGrades[conclusion], e.weights[i]*a(e, grades))
+	}
+	return outputGrades
+}
+
+// Defuzzify returns the crisp output value for the given membership grades of the output to its terms.
+// The centroid of piecewise linear output membership functions implied and aggregated by piecewise linear operators
+// is calculated exactly (see exactCentroid), otherwise the membership functions are sampled on the universe.
+// If the output has zero degree, it returns Default and ErrZeroDegree.
+func (e *Engine) Defuzzify(outputGrades []float64) (float64, error) {
+	var value float64
+	var err error
+	switch {
+	case e.Method == WeightedAverage:
+		value, err = DefuzzyWeightedAverage(e.centres, outputGrades)
+	case (e.Method == Centroid || e.Method == "") && e.isPiecewiseLinear():
+		value, err = e.exactCentroid(outputGrades)
+	default:
+		// imply every output membership function by its grade and aggregate them
+		area := make([]float64, len(e.Output.Universe))
+		for i, mf := range e.outputMFs {
+			for x, value := range mf {
+				area[x] = e.Aggregation.Apply(area[x], e.Implication.Apply(outputGrades[i], value))
+			}
+		}
+		value, err = Defuzzy(e.Output.Universe, area, e.Method)
+	}
+
+	if err == ErrZeroDegree {
+		return e.Default, err
+	}
+	return value, err
+}
+
+// Evaluate returns the crisp output value for the given membership grades of the inputs (see Infer and Defuzzify,
+// or Sugeno without crisp values in Sugeno mode).
+func (e *Engine) Evaluate(grades map[string][]float64) (float64, error) {
+	if e.IsSugeno() {
+		return e.Sugeno(grades, nil)
+	}
+	return e.Defuzzify(e.Infer(grades))
+}
+
+// EvaluateCrisp returns the crisp output value for the given crisp values of the inputs (by name).
+func (e *Engine) EvaluateCrisp(values map[string]float64) (float64, error) {
+	grades := map[string][]float64{}
+	for _, input := range e.Inputs {
+		grades[input.Name] = input.Fuzzify(values[input.Name])
+	}
+	if e.IsSugeno() {
+		return e.Sugeno(grades, values)
+	}
+	return e.Evaluate(grades)
+}
@@ -0,0 +1,945 @@
+package fuzzy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FCLResolution is the step of the discrete universes of variables read from FCL,
+// as FCL only defines the (continuous) range of a variable.
+var FCLResolution = 0.1
+
+// maxFCLUniversePoints is the maximal number of points of the universe of a variable read from FCL,
+// which keeps a huge range from exhausting the memory.
+const maxFCLUniversePoints = 1e6
+
+// fclToken is a token of FCL and the line it was found in.
+type fclToken struct {
+	text string
+	line int
+}
+
+// tokenizeFCL splits the given FCL into tokens, skipping whitespace and comments ("(* ... *)" and "// ...").
+func tokenizeFCL(input string) ([]fclToken, error) {
+	var tokens []fclToken
+	runes := []rune(input)
+	line := 1
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' && next == '*':
+			start := line
+			for i += 2; i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == ')'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("line %d: unterminated comment", start)
+			}
+			i += 2
+		case r == '/' && next == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == ':' && next == '=', r == '.' && next == '.':
+			tokens = append(tokens, fclToken{string(runes[i : i+2]), line})
+			i += 2
+		case strings.ContainsRune(":;(),*", r):
+			tokens = append(tokens, fclToken{string(r), line})
+			i++
+		case unicode.IsDigit(r), (r == '-' || r == '+' || r == '.') && unicode.IsDigit(next):
+			start := i
+			i++
+			for i < len(runes) {
+				c := runes[i]
+				if unicode.IsDigit(c) || c == '.' && (i+1 >= len(runes) || runes[i+1] != '.') {
+					i++
+				} else if (c == 'e' || c == 'E') && i+1 < len(runes) {
+					i++
+					if runes[i] == '-' || runes[i] == '+' {
+						i++
+					}
+				} else {
+					break
+				}
+			}
+			tokens = append(tokens, fclToken{string(runes[start:i]), line})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, fclToken{string(runes[start:i]), line})
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character '%c'", line, r)
+		}
+	}
+	return tokens, nil
+}
+
+// fclParser parses a token stream of FCL.
+type fclParser struct {
+	tokens []fclToken
+	pos    int
+	// operators are the operators (AND, OR, ACT and ACCU) defined so far
+	operators map[string]string
+}
+
+// peek returns the current token or "" at the end.
+func (p *fclParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos].text
+}
+
+// errorf returns an error at the line of the current token.
+func (p *fclParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// accept consumes the current token if it is the given keyword (case insensitive).
+func (p *fclParser) accept(keyword string) bool {
+	if strings.EqualFold(p.peek(), keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes the current token, which must be the given keyword.
+func (p *fclParser) expect(keyword string) error {
+	if !p.accept(keyword) {
+		return p.errorf("expected %s, found '%s'", keyword, p.peek())
+	}
+	return nil
+}
+
+// identifier consumes the current token, which must be an identifier.
+func (p *fclParser) identifier() (string, error) {
+	token := p.peek()
+	if token == "" || !(unicode.IsLetter([]rune(token)[0]) || token[0] == '_') {
+		return "", p.errorf("expected identifier, found '%s'", token)
+	}
+	p.pos++
+	return token, nil
+}
+
+// number consumes the current token, which must be a number.
+func (p *fclParser) number() (float64, error) {
+	value, err := strconv.ParseFloat(p.peek(), 64)
+	if err != nil {
+		return 0, p.errorf("expected number, found '%s'", p.peek())
+	}
+	p.pos++
+	return value, nil
+}
+
+// fclVariable is a variable of FCL while it is parsed.
+type fclVariable struct {
+	name     string
+	terms    []Term
+	hasRange bool
+	min, max float64
+	// defuzzification method and default value of an output variable
+	method       DefuzzificationMethod
+	defaultValue float64
+}
+
+// ParseFCL reads a fuzzy system in the Fuzzy Control Language (IEC 61131-7) and returns its engine.
+// The function block must have exactly one output variable. Terms are triangles or trapezoids given by their points
+// (e.g. "TERM warm := (10, 0) (20, 1) (30, 0);") or any membership function given by its name and parameters
+// (see fclShapes), e.g. "trian 10 20 30", "trape 10 15 25 30", "gauss 20 5", "gbell 5 2 20" or "sigm 0.5 20".
+// If all terms of the output are singletons (e.g. "TERM low := 20;"), the engine is in Sugeno mode with them as
+// zero-order consequents. Conditions of rules may have Hedges (e.g. "length IS very long") and rules a weight
+// (e.g. "RULE 1 : IF length IS long THEN strength IS strong WITH 0.8;").
+// The operators AND and ACT (activation, the implication) are one of TNorms, OR and ACCU (accumulation, the
+// aggregation) one of SNorms and METHOD is one of DefuzzificationMethods. Universes span the RANGE of a variable (or the corners of its terms) in steps of FCLResolution.
+func ParseFCL(reader io.Reader) (*Engine, error) {
+	input, err := ioutil.ReadAll(bufio.NewReader(reader))
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := tokenizeFCL(string(input))
+	if err != nil {
+		return nil, err
+	}
+	p := &fclParser{tokens: tokens, operators: map[string]string{}}
+
+	if err := p.expect("FUNCTION_BLOCK"); err != nil {
+		return nil, err
+	}
+	if !isFCLSection(p.peek()) {
+		if _, err := p.identifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	var inputNames, outputNames []string
+	variables := map[string]*fclVariable{}
+	var rules []Rule
+
+	for !p.accept("END_FUNCTION_BLOCK") {
+		switch {
+		case p.accept("VAR_INPUT"):
+			names, err := p.parseDeclarations()
+			if err != nil {
+				return nil, err
+			}
+			inputNames = append(inputNames, names...)
+		case p.accept("VAR_OUTPUT"):
+			names, err := p.parseDeclarations()
+			if err != nil {
+				return nil, err
+			}
+			outputNames = append(outputNames, names...)
+		case p.accept("FUZZIFY"):
+			variable, err := p.parseVariable("END_FUZZIFY")
+			if err != nil {
+				return nil, err
+			}
+			variables[variable.name] = variable
+		case p.accept("DEFUZZIFY"):
+			variable, err := p.parseVariable("END_DEFUZZIFY")
+			if err != nil {
+				return nil, err
+			}
+			variables[variable.name] = variable
+		case p.accept("RULEBLOCK"):
+			blockRules, err := p.parseRuleBlock()
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, blockRules...)
+		default:
+			return nil, p.errorf("expected VAR_INPUT, VAR_OUTPUT, FUZZIFY, DEFUZZIFY, RULEBLOCK or END_FUNCTION_BLOCK, found '%s'", p.peek())
+		}
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected '%s' after END_FUNCTION_BLOCK", p.peek())
+	}
+
+	if len(outputNames) != 1 {
+		return nil, fmt.Errorf("expected exactly one output variable, found %d", len(outputNames))
+	}
+	toVariable := func(name string) (Variable, error) {
+		variable, ok := variables[name]
+		if !ok {
+			return Variable{}, fmt.Errorf("variable %s is not fuzzified or defuzzified", name)
+		}
+		return variable.toVariable()
+	}
+
+	var inputs []Variable
+	for _, name := range inputNames {
+		input, err := toVariable(name)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, input)
+	}
+	output, err := toVariable(outputNames[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// conclusions of the rules must refer to the output variable
+	for i := range rules {
+		conclusion := strings.SplitN(rules[i].Then, " ", 2)
+		if conclusion[0] != output.Name {
+			return nil, fmt.Errorf("rule %d: %s is not the output variable", i+1, conclusion[0])
+		}
+		rules[i].Then = conclusion[1]
+	}
+
+	e, err := NewEngine(inputs, output, rules)
+	if err != nil {
+		return nil, err
+	}
+	e.Method, e.Default = variables[output.Name].method, variables[output.Name].defaultValue
+	e.And, e.Or = TNorm(p.operators["AND"]), SNorm(p.operators["OR"])
+	e.Implication, e.Aggregation = TNorm(p.operators["ACT"]), SNorm(p.operators["ACCU"])
+
+	// an output with singletons only is the one of a zero-order Sugeno model
+	consequents := map[string]Consequent{}
+	for _, term := range output.Terms {
+		if singleton, ok := term.MF.(Singleton); ok {
+			consequents[term.Name] = Consequent{Constant: singleton.Value}
+		}
+	}
+	if len(consequents) == len(output.Terms) {
+		e.Consequents = consequents
+	}
+	return e, nil
+}
+
+// isFCLSection returns whether the given token starts a section of a function block.
+func isFCLSection(token string) bool {
+	for _, section := range []string{"VAR_INPUT", "VAR_OUTPUT", "FUZZIFY", "DEFUZZIFY", "RULEBLOCK", "END_FUNCTION_BLOCK"} {
+		if strings.EqualFold(token, section) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDeclarations parses the declarations "name : REAL;" of a VAR_INPUT or VAR_OUTPUT section up to END_VAR.
+func (p *fclParser) parseDeclarations() ([]string, error) {
+	var names []string
+	for !p.accept("END_VAR") {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		if _, err := p.identifier(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// parseOperator parses ": value;" of the given operator (AND, OR, ACT or ACCU), whose value must be a TNorm (AND, ACT)
+// or SNorm (OR, ACCU). As the engine has one set of operators, it must not differ from a previous definition.
+func (p *fclParser) parseOperator(operator string) error {
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+	value, err := p.identifier()
+	if err != nil {
+		return err
+	}
+	value = strings.ToUpper(value)
+
+	var supported []string
+	if operator == "AND" || operator == "ACT" {
+		for _, t := range TNorms {
+			supported = append(supported, string(t))
+		}
+	} else {
+		for _, s := range SNorms {
+			supported = append(supported, string(s))
+		}
+	}
+	isSupported := false
+	for _, name := range supported {
+		isSupported = isSupported || name == value
+	}
+	if !isSupported {
+		return p.errorf("unsupported %s operator %s (supported: %s)", operator, value, strings.Join(supported, ", "))
+	}
+	if previous, ok := p.operators[operator]; ok && previous != value {
+		return p.errorf("%s operator %s differs from %s defined before", operator, value, previous)
+	}
+	p.operators[operator] = value
+	return p.expect(";")
+}
+
+// parseVariable parses the terms, range and (for outputs) method and default value of a FUZZIFY or DEFUZZIFY section
+// up to the given end keyword.
+func (p *fclParser) parseVariable(end string) (*fclVariable, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	variable := &fclVariable{name: name}
+
+	for !p.accept(end) {
+		switch {
+		case p.accept("TERM"):
+			term, err := p.parseTerm()
+			if err != nil {
+				return nil, err
+			}
+			variable.terms = append(variable.terms, term)
+		case p.accept("RANGE"):
+			if err := p.expect(":="); err != nil {
+				return nil, err
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			if variable.min, err = p.number(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(".."); err != nil {
+				return nil, err
+			}
+			if variable.max, err = p.number(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			if variable.min >= variable.max {
+				return nil, fmt.Errorf("variable %s: empty range", name)
+			}
+			variable.hasRange = true
+		case end == "END_DEFUZZIFY" && p.accept("METHOD"):
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			method, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			variable.method = DefuzzificationMethod(strings.ToUpper(method))
+			if !isDefuzzificationMethod(variable.method) {
+				return nil, p.errorf("unsupported defuzzification method %s (supported: %v)", method, DefuzzificationMethods)
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		case end == "END_DEFUZZIFY" && p.accept("ACCU"):
+			if err := p.parseOperator("ACCU"); err != nil {
+				return nil, err
+			}
+		case end == "END_DEFUZZIFY" && p.accept("DEFAULT"):
+			if err := p.expect(":="); err != nil {
+				return nil, err
+			}
+			if variable.defaultValue, err = p.number(); err != nil {
+				return nil, err
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.errorf("unexpected '%s' in variable %s", p.peek(), name)
+		}
+	}
+	return variable, nil
+}
+
+// isDefuzzificationMethod returns whether the given method is one of DefuzzificationMethods.
+func isDefuzzificationMethod(method DefuzzificationMethod) bool {
+	for _, m := range DefuzzificationMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// fclShapes are the membership functions of terms given by their name and parameters, e.g. "gauss 50 10"
+// (as in jFuzzyLogic), by the number of their parameters.
+var fclShapes = map[string]struct {
+	parameters int
+	mf         func(p []float64) MembershipFunction
+}{
+	"trian": {3, func(p []float64) MembershipFunction { return Triangle{p[0], p[1], p[2]} }},
+	"trape": {4, func(p []float64) MembershipFunction { return Trapezoid{p[0], p[1], p[2], p[3]} }},
+	"gauss": {2, func(p []float64) MembershipFunction { return Gaussian{p[0], p[1]} }},
+	"gbell": {3, func(p []float64) MembershipFunction { return Bell{p[0], p[1], p[2]} }},
+	"sigm":  {2, func(p []float64) MembershipFunction { return Sigmoid{p[0], p[1]} }},
+}
+
+// parseTerm parses "name := membership function;" of a term.
+func (p *fclParser) parseTerm() (Term, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return Term{}, err
+	}
+	if err := p.expect(":="); err != nil {
+		return Term{}, err
+	}
+
+	var mf MembershipFunction
+	if value, err := strconv.ParseFloat(p.peek(), 64); err == nil {
+		p.pos++
+		mf = Singleton{value}
+	} else if shape, ok := fclShapes[strings.ToLower(p.peek())]; ok {
+		p.pos++
+		parameters := make([]float64, shape.parameters)
+		for i := range parameters {
+			if parameters[i], err = p.number(); err != nil {
+				return Term{}, err
+			}
+		}
+		mf = shape.mf(parameters)
+	} else {
+		var points [][2]float64
+		for p.accept("(") {
+			x, err := p.number()
+			if err != nil {
+				return Term{}, err
+			}
+			if err := p.expect(","); err != nil {
+				return Term{}, err
+			}
+			y, err := p.number()
+			if err != nil {
+				return Term{}, err
+			}
+			if err := p.expect(")"); err != nil {
+				return Term{}, err
+			}
+			points = append(points, [2]float64{x, y})
+		}
+		if mf, err = pointsToMF(points); err != nil {
+			return Term{}, p.errorf("term %s: %v", name, err)
+		}
+	}
+	if err := mf.Validate(); err != nil {
+		return Term{}, p.errorf("term %s: %v", name, err)
+	}
+
+	if err := p.expect(";"); err != nil {
+		return Term{}, err
+	}
+	return Term{Name: name, MF: mf}, nil
+}
+
+// pointsToMF returns the triangle or trapezoid given by its points (x, y).
+// The points must be sorted by x, with y 1 for one or two points, optionally preceded and followed by a point with y 0.
+func pointsToMF(points [][2]float64) (MembershipFunction, error) {
+	var zerosBefore, ones, zerosAfter []float64
+	for i, point := range points {
+		if i > 0 && point[0] < points[i-1][0] {
+			return nil, fmt.Errorf("points are not sorted")
+		}
+		switch {
+		case point[1] == 1 && len(zerosAfter) == 0:
+			ones = append(ones, point[0])
+		case point[1] == 0 && len(ones) == 0:
+			zerosBefore = append(zerosBefore, point[0])
+		case point[1] == 0:
+			zerosAfter = append(zerosAfter, point[0])
+		default:
+			return nil, fmt.Errorf("only triangles and trapezoids can be given by points")
+		}
+	}
+	if len(ones) == 0 || len(ones) > 2 || len(zerosBefore) > 1 || len(zerosAfter) > 1 {
+		return nil, fmt.Errorf("only triangles and trapezoids can be given by points")
+	}
+
+	left, leftTop, rightTop, right := ones[0], ones[0], ones[len(ones)-1], ones[len(ones)-1]
+	if len(zerosBefore) == 1 {
+		left = zerosBefore[0]
+	}
+	if len(zerosAfter) == 1 {
+		right = zerosAfter[0]
+	}
+	if leftTop == rightTop {
+		return Triangle{left, leftTop, right}, nil
+	}
+	return Trapezoid{left, leftTop, rightTop, right}, nil
+}
+
+// parseRuleBlock parses the operators and rules of a RULEBLOCK up to END_RULEBLOCK.
+// The conclusions of the rules are returned as "variable term" (see ParseFCL).
+func (p *fclParser) parseRuleBlock() ([]Rule, error) {
+	if _, err := p.identifier(); err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for !p.accept("END_RULEBLOCK") {
+		switch {
+		case strings.Contains(" AND OR ACT ACCU ", " "+strings.ToUpper(p.peek())+" "):
+			operator := strings.ToUpper(p.peek())
+			p.pos++
+			if err := p.parseOperator(operator); err != nil {
+				return nil, err
+			}
+		case p.accept("RULE"):
+			rule, err := p.parseRule()
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		default:
+			return nil, p.errorf("unexpected '%s' in rule block", p.peek())
+		}
+	}
+	return rules, nil
+}
+
+// parseRule parses "number : IF antecedent THEN variable IS term [WITH weight];".
+func (p *fclParser) parseRule() (Rule, error) {
+	if _, err := p.number(); err != nil {
+		return Rule{}, err
+	}
+	if err := p.expect(":"); err != nil {
+		return Rule{}, err
+	}
+	if err := p.expect("IF"); err != nil {
+		return Rule{}, err
+	}
+	antecedent, err := p.parseOr()
+	if err != nil {
+		return Rule{}, err
+	}
+	if err := p.expect("THEN"); err != nil {
+		return Rule{}, err
+	}
+	variable, err := p.identifier()
+	if err != nil {
+		return Rule{}, err
+	}
+	if err := p.expect("IS"); err != nil {
+		return Rule{}, err
+	}
+	term, err := p.identifier()
+	if err != nil {
+		return Rule{}, err
+	}
+	var weight float64
+	if p.accept("WITH") {
+		if weight, err = p.number(); err != nil {
+			return Rule{}, err
+		}
+		if !(0 < weight && weight <= 1) {
+			return Rule{}, p.errorf("weight %v is not in (0, 1]", weight)
+		}
+	}
+	if err := p.expect(";"); err != nil {
+		return Rule{}, err
+	}
+	return Rule{If: antecedent, Then: variable + " " + term, Weight: weight}, nil
+}
+
+// parseOr parses a disjunction of conjunctions (AND binds stronger than OR).
+func (p *fclParser) parseOr() (Antecedent, error) {
+	var or Or
+	for {
+		and, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, and)
+		if !p.accept("OR") {
+			break
+		}
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+// parseAnd parses a conjunction of conditions.
+func (p *fclParser) parseAnd() (Antecedent, error) {
+	var and And
+	for {
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, factor)
+		if !p.accept("AND") {
+			break
+		}
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+// parseFactor parses "NOT factor", "(antecedent)" or "variable IS [NOT] [hedges] term".
+func (p *fclParser) parseFactor() (Antecedent, error) {
+	if p.accept("NOT") {
+		factor, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return Not{factor}, nil
+	}
+	if p.accept("(") {
+		antecedent, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return antecedent, p.expect(")")
+	}
+
+	variable, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("IS"); err != nil {
+		return nil, err
+	}
+	negated := p.accept("NOT")
+	var hedges []Hedge
+	for {
+		// the last word of the condition is the term, even if it is named like a hedge
+		hedge, ok := isHedge(p.peek())
+		if !ok || p.pos+1 >= len(p.tokens) || strings.Contains(" ) AND OR THEN ", " "+strings.ToUpper(p.tokens[p.pos+1].text)+" ") {
+			break
+		}
+		p.pos++
+		hedges = append(hedges, hedge)
+	}
+	term := Any
+	if !p.accept(Any) {
+		if term, err = p.identifier(); err != nil {
+			return nil, err
+		}
+	}
+	if negated {
+		return Not{Is{Variable: variable, Term: term, Hedges: hedges}}, nil
+	}
+	return Is{Variable: variable, Term: term, Hedges: hedges}, nil
+}
+
+// toVariable returns the variable with a universe spanning its range (or the corners of its terms) in steps of FCLResolution.
+func (v *fclVariable) toVariable() (Variable, error) {
+	if len(v.terms) == 0 {
+		return Variable{}, fmt.Errorf("variable %s has no terms", v.name)
+	}
+	if !v.hasRange {
+		// only the corners of triangles and trapezoids bound the range
+		var corners []float64
+		for _, term := range v.terms {
+			switch mf := term.MF.(type) {
+			case Triangle:
+				corners = append(corners, mf.Left, mf.Right)
+			case Trapezoid:
+				corners = append(corners, mf.Left, mf.Right)
+			case Singleton:
+				corners = append(corners, mf.Value)
+			default:
+				return Variable{}, fmt.Errorf("variable %s: missing RANGE", v.name)
+			}
+		}
+		v.min, v.max = corners[0], corners[0]
+		for _, corner := range corners {
+			v.min, v.max = math.Min(v.min, corner), math.Max(v.max, corner)
+		}
+	}
+	if v.min >= v.max || FCLResolution <= 0 {
+		return Variable{}, fmt.Errorf("variable %s: empty range", v.name)
+	}
+	if points := (v.max - v.min) / FCLResolution; !(points <= maxFCLUniversePoints) {
+		return Variable{}, fmt.Errorf("variable %s: range too large for a resolution of %g", v.name, FCLResolution)
+	}
+	return Variable{Name: v.name, Universe: Arrange(v.min, v.max+FCLResolution/2, FCLResolution), Terms: v.terms}, nil
+}
+
+// formatFCLNumber formats a number for FCL.
+func formatFCLNumber(x float64) string {
+	return strconv.FormatFloat(x, 'g', 10, 64)
+}
+
+// formatFCLTerm returns the membership function of the given term in FCL:
+// triangles and trapezoids by their points, other shapes by their name and parameters (see fclShapes).
+func formatFCLTerm(term Term) (string, error) {
+	var corners []float64
+	switch mf := term.MF.(type) {
+	case Triangle:
+		corners = []float64{mf.Left, mf.Middle, mf.Middle, mf.Right}
+	case Trapezoid:
+		corners = []float64{mf.Left, mf.LeftTop, mf.RightTop, mf.Right}
+	case Gaussian:
+		return formatFCLShape("gauss", mf.Mean, mf.Sigma), nil
+	case Bell:
+		return formatFCLShape("gbell", mf.Width, mf.Slope, mf.Center), nil
+	case Sigmoid:
+		return formatFCLShape("sigm", mf.Slope, mf.Center), nil
+	case Singleton:
+		return formatFCLNumber(mf.Value), nil
+	default:
+		return "", fmt.Errorf("term %s: membership function %T can not be written", term.Name, term.MF)
+	}
+
+	points := []string{}
+	if corners[0] != corners[1] {
+		points = append(points, fmt.Sprintf("(%s, 0)", formatFCLNumber(corners[0])))
+	}
+	points = append(points, fmt.Sprintf("(%s, 1)", formatFCLNumber(corners[1])))
+	if corners[2] != corners[1] {
+		points = append(points, fmt.Sprintf("(%s, 1)", formatFCLNumber(corners[2])))
+	}
+	if corners[3] != corners[2] {
+		points = append(points, fmt.Sprintf("(%s, 0)", formatFCLNumber(corners[3])))
+	}
+	return strings.Join(points, " "), nil
+}
+
+// formatFCLShape returns the given shape and its parameters.
+func formatFCLShape(shape string, parameters ...float64) string {
+	parts := []string{shape}
+	for _, parameter := range parameters {
+		parts = append(parts, formatFCLNumber(parameter))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatFCLAntecedent returns the given antecedent in FCL. Conditions with the term Any are omitted.
+func formatFCLAntecedent(antecedent Antecedent) (string, error) {
+	switch a := antecedent.(type) {
+	case Is:
+		if a.Term == Any {
+			return "", nil
+		}
+		return a.Variable + " IS " + formatFCLHedges(a.Hedges) + a.Term, nil
+	case Not:
+		if is, ok := a.Antecedent.(Is); ok && is.Term != Any {
+			return is.Variable + " IS NOT " + formatFCLHedges(is.Hedges) + is.Term, nil
+		}
+		negated, err := formatFCLAntecedent(a.Antecedent)
+		if err != nil || negated == "" {
+			return "", fmt.Errorf("negation of a wildcard can not be written")
+		}
+		return "NOT (" + negated + ")", nil
+	case And, Or:
+		children, operator := []Antecedent(nil), " AND "
+		if and, ok := a.(And); ok {
+			children = and
+		} else {
+			children, operator = a.(Or), " OR "
+		}
+
+		var parts []string
+		for _, child := range children {
+			part, err := formatFCLAntecedent(child)
+			if err != nil {
+				return "", err
+			}
+			if part == "" {
+				if operator == " OR " {
+					return "", fmt.Errorf("disjunction with a wildcard can not be written")
+				}
+				continue
+			}
+			if _, ok := child.(Or); ok || operator == " OR " && isAnd(child) {
+				part = "(" + part + ")"
+			}
+			parts = append(parts, part)
+		}
+		return strings.Join(parts, operator), nil
+	}
+	return "", fmt.Errorf("unknown antecedent %T", antecedent)
+}
+
+// formatFCLHedges returns the given hedges in FCL, each followed by a space.
+func formatFCLHedges(hedges []Hedge) string {
+	var b strings.Builder
+	for _, hedge := range hedges {
+		b.WriteString(string(hedge) + " ")
+	}
+	return b.String()
+}
+
+// isAnd returns whether the given antecedent is a conjunction.
+func isAnd(antecedent Antecedent) bool {
+	_, ok := antecedent.(And)
+	return ok
+}
+
+// WriteFCL writes the given engine as function block with the given name in the Fuzzy Control Language (IEC 61131-7),
+// which can be read by ParseFCL. Universes are written as their range.
+func WriteFCL(writer io.Writer, e *Engine, name string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "FUNCTION_BLOCK %s\n\nVAR_INPUT\n", name)
+	for _, input := range e.Inputs {
+		fmt.Fprintf(&b, "\t%s : REAL;\n", input.Name)
+	}
+	fmt.Fprintf(&b, "END_VAR\n\nVAR_OUTPUT\n\t%s : REAL;\nEND_VAR\n", e.Output.Name)
+
+	writeVariable := func(section string, variable Variable, extra string) error {
+		fmt.Fprintf(&b, "\n%s %s\n", section, variable.Name)
+		if len(variable.Universe) > 0 {
+			fmt.Fprintf(&b, "\tRANGE := (%s .. %s);\n", formatFCLNumber(variable.Universe[0]), formatFCLNumber(variable.Universe[len(variable.Universe)-1]))
+		}
+		for _, term := range variable.Terms {
+			mf, err := formatFCLTerm(term)
+			if err != nil {
+				return fmt.Errorf("variable %s: %v", variable.Name, err)
+			}
+			fmt.Fprintf(&b, "\tTERM %s := %s;\n", term.Name, mf)
+		}
+		fmt.Fprintf(&b, "%sEND_%s\n", extra, section)
+		return nil
+	}
+	for _, input := range e.Inputs {
+		if err := writeVariable("FUZZIFY", input, ""); err != nil {
+			return err
+		}
+	}
+	output, method := e.Output, e.Method
+	if method == "" {
+		method = Centroid
+	}
+	if e.IsSugeno() {
+		// the consequents of a zero-order Sugeno model are singletons averaged by their weights
+		output.Terms, method = nil, WeightedAverage
+		for _, term := range e.Output.Terms {
+			consequent := e.Consequents[term.Name]
+			if len(consequent.Coefficients) > 0 {
+				return fmt.Errorf("term %s: first-order consequents can not be written", term.Name)
+			}
+			output.Terms = append(output.Terms, Term{Name: term.Name, MF: Singleton{consequent.Constant}})
+		}
+	}
+	if err := writeVariable("DEFUZZIFY", output, fmt.Sprintf("\tMETHOD : %s;\n\tDEFAULT := %s;\n", method, formatFCLNumber(e.Default))); err != nil {
+		return err
+	}
+
+	and, or, implication, aggregation := e.And, e.Or, e.Implication, e.Aggregation
+	if and == "" {
+		and = Minimum
+	}
+	if or == "" {
+		or = Maximum
+	}
+	if implication == "" {
+		implication = Minimum
+	}
+	if aggregation == "" {
+		aggregation = Maximum
+	}
+	fmt.Fprintf(&b, "\nRULEBLOCK rules\n\tAND : %s;\n\tOR : %s;\n\tACT : %s;\n\tACCU : %s;\n", and, or, implication, aggregation)
+	for i, rule := range e.Rules {
+		antecedent, err := formatFCLAntecedent(rule.If)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i+1, err)
+		}
+		if antecedent == "" {
+			return fmt.Errorf("rule %d: rule without conditions can not be written", i+1)
+		}
+		weight := ""
+		if rule.Weight != 0 && rule.Weight != 1 {
+			weight = " WITH " + formatFCLNumber(rule.Weight)
+		}
+		fmt.Fprintf(&b, "\tRULE %d : IF %s THEN %s IS %s%s;\n", i+1, antecedent, e.Output.Name, rule.Then, weight)
+	}
+	b.WriteString("END_RULEBLOCK\n\nEND_FUNCTION_BLOCK\n")
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
@@ -11,6 +11,8 @@ import (
 // containing the left, middle and right values of the triangle.
 // It returns a float64 array of the triangular membership function values.
 // In case LMR is malformatted, an error is returned.
+//
+// Deprecated: Use DetMF with a Triangle, which also takes fractional corners.
 func DetTriangleMF(inV []float64, LMR []int) ([]float64, error) {
 	L, M, R := float64(LMR[0]), float64(LMR[1]), float64(LMR[2])
 	if len(LMR) != 3 || L > M || M > R {
@@ -119,73 +121,68 @@ func Arrange(start, stop, step float64) []float64 {
 	return rnge
 }
 
+// PredictabilityVariable is the linguistic variable of the password predictability (hard, medium, easy).
+var PredictabilityVariable = Variable{
+	Name:     "predictability",
+	Universe: Arrange(0, 100, .1),
+	Terms:    []Term{{Name: "hard", MF: Triangle{30, 30, 50}}, {Name: "medium", MF: Triangle{30, 50, 70}}, {Name: "easy", MF: Triangle{50, 70, 70}}}}
+
+// LengthVariable is the linguistic variable of the password length (very short, short, medium, long, very long).
+var LengthVariable = Variable{
+	Name:     "length",
+	Universe: Arrange(0., 27., .1),
+	Terms: []Term{{Name: "veryShort", MF: Triangle{2, 2, 6}}, {Name: "short", MF: Triangle{4, 8, 12}}, {Name: "medium", MF: Triangle{10, 14, 18}},
+		{Name: "long", MF: Triangle{16, 20, 24}}, {Name: "veryLong", MF: Triangle{22, 26, 26}}}}
+
+// ComplexityVariable is the linguistic variable of the password complexity of complexity model v1
+// (very simple, simple, medium, complex, very complex).
+var ComplexityVariable = Variable{
+	Name:     "complexity",
+	Universe: Arrange(0., 680., .1),
+	Terms: []Term{{Name: "verySimple", MF: Triangle{5, 5, 173}}, {Name: "simple", MF: Triangle{5, 173, 341}}, {Name: "medium", MF: Triangle{173, 341, 509}},
+		{Name: "complex", MF: Triangle{341, 509, 677}}, {Name: "veryComplex", MF: Triangle{509, 677, 677}}}}
+
+// ComplexityV2Variable is the linguistic variable of the password complexity (between 0 and 100) of complexity model v2.
+// Its peaks are calibrated on the complexities of the shipped password lists and of random passwords: half of the
+// top 50,000 passwords are very simple (45), 90% at most simple (65) and 99% at most medium (79), random passwords of
+// 8 to 16 letters and digits are complex (their median is 85) and those of all character sets very complex (95).
+var ComplexityV2Variable = Variable{
+	Name:     "complexity",
+	Universe: Arrange(0., 101., .1),
+	Terms: []Term{{Name: "verySimple", MF: Triangle{45, 45, 65}}, {Name: "simple", MF: Triangle{45, 65, 79}}, {Name: "medium", MF: Triangle{65, 79, 85}},
+		{Name: "complex", MF: Triangle{79, 85, 95}}, {Name: "veryComplex", MF: Triangle{85, 95, 95}}}}
+
+// BreachVariable is the linguistic variable of the breach status, which is crisp: a password is either not breached (0) or breached (1).
+var BreachVariable = Variable{
+	Name:     "breach",
+	Universe: []float64{0, 1},
+	Terms:    []Term{{Name: "notBreached", MF: Triangle{0, 0, 1}}, {Name: "breached", MF: Triangle{0, 1, 1}}}}
+
 //CalculateMembershipGradesForPredictability returns an float64 array of membership grades of given predictability
 func CalculateMembershipGradesForPredictability(predictability float64) []float64 {
-	// password predictability (P)
-	P := Arrange(0, 100, .1)
-
-	// define triangle ranges (H=hard, M=medium, E=easy)
-	PHVar, PMVar, PEVar := []int{30, 30, 50}, []int{30, 50, 70}, []int{50, 70, 70}
-
-	// input membership functions
-	PH, _ := DetTriangleMF(P, PHVar)
-	PM, _ := DetTriangleMF(P, PMVar)
-	PE, _ := DetTriangleMF(P, PEVar)
-
-	// determine membership grades
-	var PList []float64
-	PList = append(PList, DetMFGrad(P, PH, predictability))
-	PList = append(PList, DetMFGrad(P, PM, predictability))
-	PList = append(PList, DetMFGrad(P, PE, predictability))
-	return PList
+	return PredictabilityVariable.Fuzzify(predictability)
 }
 
 //CalculateMembershipGradesForLength returns a float64 array of the membership grades of given length
 func CalculateMembershipGradesForLength(length float64) []float64 {
-	// password length
-	L := Arrange(0., 27., .1)
-
-	// define triangle ranges (Vs=very short, S=short, M=medium, L=long, Vl=very long)
-	LVsVar, LSVar, LMVar, LLVar, LVlVar := []int{2, 2, 6}, []int{4, 8, 12}, []int{10, 14, 18}, []int{16, 20, 24}, []int{22, 26, 26}
-
-	// input membership functions
-	LVs, _ := DetTriangleMF(L, LVsVar)
-	LS, _ := DetTriangleMF(L, LSVar)
-	LM, _ := DetTriangleMF(L, LMVar)
-	LL, _ := DetTriangleMF(L, LLVar)
-	LVl, _ := DetTriangleMF(L, LVlVar)
-
-	// determine membership grades
-	var LList []float64
-	LList = append(LList, DetMFGrad(L, LVs, float64(length)))
-	LList = append(LList, DetMFGrad(L, LS, float64(length)))
-	LList = append(LList, DetMFGrad(L, LM, float64(length)))
-	LList = append(LList, DetMFGrad(L, LL, float64(length)))
-	LList = append(LList, DetMFGrad(L, LVl, float64(length)))
-	return LList
+	return LengthVariable.Fuzzify(length)
 }
 
 //CalculateMembershipGradesForComplexity returns a float64 array of the membership grades of given complexity
 func CalculateMembershipGradesForComplexity(complexity float64) []float64 {
-	// password complexity
-	C := Arrange(0., 680., .1)
-
-	// define triangle ranges (Vs=very simple, S=simple, M=medium, C=complex, Vc=very complex)
-	CVsVar, CSVar, CMVar, CCVar, CVcVar := []int{5, 5, 173}, []int{5, 173, 341}, []int{173, 341, 509}, []int{341, 509, 677}, []int{509, 677, 677}
-
-	// input Membership functions
-	CVs, _ := DetTriangleMF(C, CVsVar)
-	CS, _ := DetTriangleMF(C, CSVar)
-	CM, _ := DetTriangleMF(C, CMVar)
-	CC, _ := DetTriangleMF(C, CCVar)
-	CVc, _ := DetTriangleMF(C, CVcVar)
-
-	// determine membership grades (membershipList5)
-	var CList []float64
-	CList = append(CList, DetMFGrad(C, CVs, complexity))
-	CList = append(CList, DetMFGrad(C, CS, complexity))
-	CList = append(CList, DetMFGrad(C, CM, complexity))
-	CList = append(CList, DetMFGrad(C, CC, complexity))
-	CList = append(CList, DetMFGrad(C, CVc, complexity))
-	return CList
+	return ComplexityVariable.Fuzzify(complexity)
+}
+
+//CalculateMembershipGradesForBreach returns a float64 array of the membership grades of given breach prevalence
+func CalculateMembershipGradesForBreach(breachCount float64) []float64 {
+	// breach status is crisp: a password is either (0) not breached or (1) breached
+	if breachCount > 0 {
+		return []float64{0, 1}
+	}
+	return []float64{1, 0}
+}
+
+//CalculateMembershipGradesForComplexityV2 returns a float64 array of the membership grades of given complexity of complexity model v2
+func CalculateMembershipGradesForComplexityV2(complexity float64) []float64 {
+	return ComplexityV2Variable.Fuzzify(complexity)
 }
@@ -0,0 +1,51 @@
+package fuzzy
+
+import (
+	"log"
+	"math"
+	"strings"
+)
+
+// Hedge is a linguistic hedge modifying the membership grade of a term in a condition, e.g. "very" in
+// "length IS very long".
+type Hedge string
+
+const (
+	// Very concentrates a term to the square of its membership grade.
+	Very Hedge = "very"
+	// Extremely concentrates a term to the cube of its membership grade.
+	Extremely Hedge = "extremely"
+	// Somewhat dilates a term to the square root of its membership grade.
+	Somewhat Hedge = "somewhat"
+	// NotHedge negates a term to 1 minus its membership grade.
+	NotHedge Hedge = "not"
+)
+
+// Hedges are all hedges.
+var Hedges = []Hedge{Very, Extremely, Somewhat, NotHedge}
+
+// Apply returns the given membership grade modified by the hedge. It panics if the hedge is unknown.
+func (h Hedge) Apply(grade float64) float64 {
+	switch h {
+	case Very:
+		return grade * grade
+	case Extremely:
+		return grade * grade * grade
+	case Somewhat:
+		return math.Sqrt(grade)
+	case NotHedge:
+		return 1 - grade
+	}
+	log.Panicf("Unknown hedge %s", string(h))
+	return 0
+}
+
+// isHedge returns whether the given word is a hedge (case insensitive), and which one.
+func isHedge(word string) (Hedge, bool) {
+	for _, hedge := range Hedges {
+		if strings.EqualFold(word, string(hedge)) {
+			return hedge, true
+		}
+	}
+	return "", false
+}
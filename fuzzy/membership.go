@@ -0,0 +1,172 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math"
+)
+
+// MembershipFunction is the membership function of a linguistic term, mapping a value to its membership grade in [0, 1].
+type MembershipFunction interface {
+	// Grade returns the membership grade of the given value.
+	Grade(x float64) float64
+	// Validate returns an error if the parameters of the membership function are invalid.
+	Validate() error
+}
+
+// Triangle is a triangular membership function with the left, middle and right corner of the triangle.
+// A triangle with equal left and middle corner is 1 on the very left of the graph (a left shoulder),
+// one with equal middle and right corner is 1 on the very right of the graph (a right shoulder).
+type Triangle struct {
+	Left, Middle, Right float64
+}
+
+// Grade returns the membership grade of the given value.
+func (t Triangle) Grade(x float64) float64 {
+	return Trapezoid{t.Left, t.Middle, t.Middle, t.Right}.Grade(x)
+}
+
+// Validate returns an error if the corners are not finite or not sorted.
+func (t Triangle) Validate() error {
+	return validateCorners("triangle", t.Left, t.Middle, t.Right)
+}
+
+// Trapezoid is a trapezoidal membership function with the left, left top, right top and right corner of the trapezoid.
+// Like a Triangle, a trapezoid with equal left corners is a left shoulder and one with equal right corners is a right shoulder.
+type Trapezoid struct {
+	Left, LeftTop, RightTop, Right float64
+}
+
+// Grade returns the membership grade of the given value.
+func (t Trapezoid) Grade(x float64) float64 {
+	switch {
+	case t.LeftTop <= x && x <= t.RightTop, x <= t.LeftTop && t.Left == t.LeftTop, x >= t.RightTop && t.RightTop == t.Right:
+		return 1
+	case t.Left < x && x < t.LeftTop:
+		return (x - t.Left) / (t.LeftTop - t.Left)
+	case t.RightTop < x && x < t.Right:
+		return (t.Right - x) / (t.Right - t.RightTop)
+	}
+	return 0
+}
+
+// Validate returns an error if the corners are not finite or not sorted.
+func (t Trapezoid) Validate() error {
+	return validateCorners("trapezoid", t.Left, t.LeftTop, t.RightTop, t.Right)
+}
+
+// Gaussian is the membership function exp(-(x-Mean)²/(2·Sigma²)).
+type Gaussian struct {
+	Mean, Sigma float64
+}
+
+// Grade returns the membership grade of the given value.
+func (g Gaussian) Grade(x float64) float64 {
+	return math.Exp(-(x - g.Mean) * (x - g.Mean) / (2 * g.Sigma * g.Sigma))
+}
+
+// Validate returns an error if the parameters are not finite or Sigma is not positive.
+func (g Gaussian) Validate() error {
+	if !isFinite(g.Mean, g.Sigma) || g.Sigma <= 0 {
+		return fmt.Errorf("invalid gaussian: mean %v and sigma %v must be finite, sigma must be positive", g.Mean, g.Sigma)
+	}
+	return nil
+}
+
+// Bell is the generalised bell membership function 1/(1+|(x-Center)/Width|^(2·Slope)).
+type Bell struct {
+	Width, Slope, Center float64
+}
+
+// Grade returns the membership grade of the given value.
+func (b Bell) Grade(x float64) float64 {
+	return 1 / (1 + math.Pow(math.Abs((x-b.Center)/b.Width), 2*b.Slope))
+}
+
+// Validate returns an error if the parameters are not finite or Width or Slope are not positive.
+func (b Bell) Validate() error {
+	if !isFinite(b.Width, b.Slope, b.Center) || b.Width <= 0 || b.Slope <= 0 {
+		return fmt.Errorf("invalid bell: width %v, slope %v and center %v must be finite, width and slope must be positive", b.Width, b.Slope, b.Center)
+	}
+	return nil
+}
+
+// Sigmoid is the membership function 1/(1+exp(-Slope·(x-Center))), a right shoulder for a positive slope
+// and a left shoulder for a negative one.
+type Sigmoid struct {
+	Slope, Center float64
+}
+
+// Grade returns the membership grade of the given value.
+func (s Sigmoid) Grade(x float64) float64 {
+	return 1 / (1 + math.Exp(-s.Slope*(x-s.Center)))
+}
+
+// Validate returns an error if the parameters are not finite or Slope is zero.
+func (s Sigmoid) Validate() error {
+	if !isFinite(s.Slope, s.Center) || s.Slope == 0 {
+		return fmt.Errorf("invalid sigmoid: slope %v and center %v must be finite, slope must not be zero", s.Slope, s.Center)
+	}
+	return nil
+}
+
+// Singleton is the membership function of a single crisp value, e.g. of an output term in Sugeno mode.
+type Singleton struct {
+	Value float64
+}
+
+// Grade returns 1 for the value of the singleton, otherwise 0.
+func (s Singleton) Grade(x float64) float64 {
+	if x == s.Value {
+		return 1
+	}
+	return 0
+}
+
+// Validate returns an error if the value is not finite.
+func (s Singleton) Validate() error {
+	if !isFinite(s.Value) {
+		return fmt.Errorf("invalid singleton: value %v must be finite", s.Value)
+	}
+	return nil
+}
+
+// validateCorners returns an error if the given corners of a shape are not finite or not sorted.
+func validateCorners(shape string, corners ...float64) error {
+	if !isFinite(corners...) {
+		return fmt.Errorf("invalid %s: corners %v must be finite", shape, corners)
+	}
+	for i := 1; i < len(corners); i++ {
+		if corners[i] < corners[i-1] {
+			return fmt.Errorf("invalid %s: corners %v must be sorted", shape, corners)
+		}
+	}
+	return nil
+}
+
+// isFinite returns whether all given values are neither infinite nor NaN.
+func isFinite(values ...float64) bool {
+	for _, value := range values {
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// DetMF calculates the given membership function (MF) for given float64 array inV.
+// It returns a float64 array of the membership function values.
+// In case the membership function is invalid, an error is returned.
+func DetMF(inV []float64, mf MembershipFunction) ([]float64, error) {
+	if mf == nil {
+		return nil, fmt.Errorf("missing membership function")
+	}
+	if err := mf.Validate(); err != nil {
+		return nil, err
+	}
+
+	MFVal := make([]float64, len(inV))
+	for Pos, x := range inV {
+		MFVal[Pos] = mf.Grade(x)
+	}
+	return MFVal, nil
+}
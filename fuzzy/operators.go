@@ -0,0 +1,84 @@
+package fuzzy
+
+import (
+	"log"
+	"math"
+)
+
+// TNorm is a triangular norm combining membership grades conjunctively (AND and implication),
+// named as in the Fuzzy Control Language.
+type TNorm string
+
+const (
+	// Minimum is the t-norm min(a, b) (the default).
+	Minimum TNorm = "MIN"
+	// Product is the t-norm a·b.
+	Product TNorm = "PROD"
+	// BoundedDifference is the Łukasiewicz t-norm max(0, a+b-1).
+	BoundedDifference TNorm = "BDIF"
+	// HamacherProduct is the Hamacher t-norm a·b/(a+b-a·b).
+	HamacherProduct TNorm = "HAMACHER"
+)
+
+// TNorms are all t-norms.
+var TNorms = []TNorm{Minimum, Product, BoundedDifference, HamacherProduct}
+
+// Apply returns the t-norm of the given membership grades. It panics if the t-norm is unknown.
+func (t TNorm) Apply(a, b float64) float64 {
+	switch t {
+	case Minimum, "":
+		return math.Min(a, b)
+	case Product:
+		return a * b
+	case BoundedDifference:
+		return math.Max(0, a+b-1)
+	case HamacherProduct:
+		if a == 0 && b == 0 {
+			return 0
+		}
+		return a * b / (a + b - a*b)
+	}
+	log.Panicf("Unknown t-norm %s", string(t))
+	return 0
+}
+
+// SNorm is a triangular conorm combining membership grades disjunctively (OR and aggregation),
+// named as in the Fuzzy Control Language.
+type SNorm string
+
+const (
+	// Maximum is the s-norm max(a, b) (the default).
+	Maximum SNorm = "MAX"
+	// ProbabilisticSum is the s-norm a+b-a·b.
+	ProbabilisticSum SNorm = "ASUM"
+	// BoundedSum is the Łukasiewicz s-norm min(1, a+b).
+	BoundedSum SNorm = "BSUM"
+	// HamacherSum is the Hamacher s-norm (a+b-2·a·b)/(1-a·b).
+	HamacherSum SNorm = "HAMACHER"
+	// Sum is a+b, which is not bounded by 1 and thus no s-norm, but the common sum-based aggregation.
+	Sum SNorm = "SUM"
+)
+
+// SNorms are all s-norms.
+var SNorms = []SNorm{Maximum, ProbabilisticSum, BoundedSum, HamacherSum, Sum}
+
+// Apply returns the s-norm of the given membership grades. It panics if the s-norm is unknown.
+func (s SNorm) Apply(a, b float64) float64 {
+	switch s {
+	case Maximum, "":
+		return math.Max(a, b)
+	case ProbabilisticSum:
+		return a + b - a*b
+	case BoundedSum:
+		return math.Min(1, a+b)
+	case HamacherSum:
+		if a == 1 && b == 1 {
+			return 1
+		}
+		return (a + b - 2*a*b) / (1 - a*b)
+	case Sum:
+		return a + b
+	}
+	log.Panicf("Unknown s-norm %s", string(s))
+	return 0
+}
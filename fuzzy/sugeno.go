@@ -0,0 +1,85 @@
+package fuzzy
+
+import (
+	"fmt"
+)
+
+// Consequent is the crisp consequent of an output term in Sugeno mode: Constant plus the values of the inputs (by name)
+// weighted by Coefficients. Without coefficients, it is a zero-order consequent, otherwise a first-order one.
+type Consequent struct {
+	Constant     float64
+	Coefficients map[string]float64
+}
+
+// value returns the consequent for the given crisp values of the inputs, which may be nil for a zero-order consequent.
+func (c Consequent) value(values map[string]float64) (float64, error) {
+	result := c.Constant
+	if len(c.Coefficients) > 0 && values == nil {
+		return 0, fmt.Errorf("first-order consequent needs the crisp values of the inputs")
+	}
+	for name, coefficient := range c.Coefficients {
+		result += coefficient * values[name]
+	}
+	return result, nil
+}
+
+// IsSugeno returns whether the engine is in Sugeno mode, i.e. has Consequents.
+func (e *Engine) IsSugeno() bool {
+	return e.Consequents != nil
+}
+
+// Sugeno returns the crisp output value in Sugeno mode for the given membership grades of the inputs (see Infer) and
+// their crisp values (only needed for first-order consequents, may be nil otherwise): the average of the consequents
+// of the rules weighted by their activations
+// multiplied by the weights of the rules. If no rule is activated, it returns Default and ErrZeroDegree.
+func (e *Engine) Sugeno(grades map[string][]float64, values map[string]float64) (float64, error) {
+	for name, input := range e.inputs {
+		if len(grades[name]) != len(input.Terms) {
+			return 0, fmt.Errorf("expected %d membership grades of input variable %s, but found %d instead", len(input.Terms), name, len(grades[name]))
+		}
+	}
+
+	sum, weightedSum := 0.0, 0.0
+	for i, a := range e.activations {
+		weight := e.weights[i] * a(e, grades)
+		if weight == 0 {
+			continue
+		}
+		term := e.Rules[i].Then
+		consequent, ok := e.Consequents[term]
+		if !ok {
+			return 0, fmt.Errorf("missing consequent of term %s of variable %s", term, e.Output.Name)
+		}
+		value, err := consequent.value(values)
+		if err != nil {
+			return 0, fmt.Errorf("term %s of variable %s: %v", term, e.Output.Name, err)
+		}
+		sum += weight
+		weightedSum += weight * value
+	}
+	if sum == 0 {
+		return e.Default, ErrZeroDegree
+	}
+	return weightedSum / sum, nil
+}
+
+// DeriveConsequents returns zero-order consequents of the output terms approximating the Mamdani inference:
+// the crisp value of every output membership function by Method (the centroid if empty).
+// Setting them as Consequents turns the engine into Sugeno mode.
+func (e *Engine) DeriveConsequents() (map[string]Consequent, error) {
+	consequents := map[string]Consequent{}
+	for i, term := range e.Output.Terms {
+		var value float64
+		var err error
+		if e.Method == WeightedAverage {
+			value = e.centres[i]
+		} else {
+			value, err = Defuzzy(e.Output.Universe, e.outputMFs[i], e.Method)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("term %s of variable %s: %v", term.Name, e.Output.Name, err)
+		}
+		consequents[term.Name] = Consequent{Constant: value}
+	}
+	return consequents, nil
+}
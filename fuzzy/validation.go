@@ -0,0 +1,165 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math"
+)
+
+// RuleAnalysis is the analysis of the rules of an engine on the combinations of terms of its inputs,
+// which are given by the indices of the terms in the order of the inputs. Rules are given by their indices.
+type RuleAnalysis struct {
+	// Combinations is the number of combinations of terms of the inputs.
+	Combinations int
+	// Uncovered are the combinations activating no rule.
+	Uncovered [][]int
+	// Overlaps are pairs of rules with the same conclusion activated by a common combination.
+	Overlaps [][2]int
+	// Conflicts are pairs of rules with different conclusions activated by a common combination.
+	Conflicts [][2]int
+	// Unreachable are the rules activated by no combination.
+	Unreachable []int
+}
+
+// AnalyzeRules enumerates all combinations of terms of the inputs and returns which rules each activates, i.e. whose
+// activation is positive if the membership grades of these terms are 1 and those of all others 0.
+func (e *Engine) AnalyzeRules() RuleAnalysis {
+	var analysis RuleAnalysis
+	reached := make([]bool, len(e.Rules))
+	overlaps, conflicts := map[[2]int]bool{}, map[[2]int]bool{}
+	for _, input := range e.Inputs {
+		if len(input.Terms) == 0 {
+			return analysis
+		}
+	}
+
+	combination := make([]int, len(e.Inputs))
+	for {
+		analysis.Combinations++
+		grades := map[string][]float64{}
+		for i, input := range e.Inputs {
+			grades[input.Name] = make([]float64, len(input.Terms))
+			grades[input.Name][combination[i]] = 1
+		}
+
+		var activated []int
+		for i, a := range e.activations {
+			if a(e, grades) > 0 {
+				activated = append(activated, i)
+				reached[i] = true
+			}
+		}
+		if len(activated) == 0 {
+			analysis.Uncovered = append(analysis.Uncovered, append([]int{}, combination...))
+		}
+		for i, first := range activated {
+			for _, second := range activated[i+1:] {
+				pair := [2]int{first, second}
+				if e.conclusions[first] == e.conclusions[second] && !overlaps[pair] {
+					overlaps[pair] = true
+					analysis.Overlaps = append(analysis.Overlaps, pair)
+				} else if e.conclusions[first] != e.conclusions[second] && !conflicts[pair] {
+					conflicts[pair] = true
+					analysis.Conflicts = append(analysis.Conflicts, pair)
+				}
+			}
+		}
+
+		// next combination, the last input changing fastest
+		i := len(combination) - 1
+		for ; i >= 0; i-- {
+			combination[i]++
+			if combination[i] < len(e.Inputs[i].Terms) {
+				break
+			}
+			combination[i] = 0
+		}
+		if i < 0 {
+			break
+		}
+	}
+
+	for i, r := range reached {
+		if !r {
+			analysis.Unreachable = append(analysis.Unreachable, i)
+		}
+	}
+	return analysis
+}
+
+// MonotonicityViolation is a pair of crisp inputs, differing only in one input variable, whose outputs violate the
+// expected monotonicity in it.
+type MonotonicityViolation struct {
+	Lower, Higher             map[string]float64
+	LowerOutput, HigherOutput float64
+}
+
+// CheckMonotonicity samples the crisp inputs of the engine on a grid of the given number of values per input variable
+// (spanning its universe, or all values of a smaller universe) and returns the neighbouring grid points where the
+// output decreases by more than the given tolerance while the given input increases, or increases if the output is
+// expected to be decreasing. Outputs of inputs activating no rule are Default.
+// It returns an error if the input is unknown, there are less than two samples or the inference fails.
+func (e *Engine) CheckMonotonicity(input string, decreasing bool, samples int, tolerance float64) ([]MonotonicityViolation, error) {
+	if _, ok := e.inputs[input]; !ok {
+		return nil, fmt.Errorf("unknown input variable %s", input)
+	}
+	if samples < 2 {
+		return nil, fmt.Errorf("expected at least 2 samples, but found %d", samples)
+	}
+
+	// the grid values of every input, spanning its universe
+	grid := make([][]float64, len(e.Inputs))
+	for i, variable := range e.Inputs {
+		if len(variable.Universe) == 0 {
+			return nil, fmt.Errorf("input variable %s has no universe", variable.Name)
+		}
+		if len(variable.Universe) <= samples {
+			grid[i] = variable.Universe
+			continue
+		}
+		lower, upper := variable.Universe[0], variable.Universe[len(variable.Universe)-1]
+		for k := 0; k < samples; k++ {
+			grid[i] = append(grid[i], lower+(upper-lower)*float64(k)/float64(samples-1))
+		}
+	}
+
+	// values returns the crisp inputs of grid point n, the last input changing fastest
+	values := func(n int) map[string]float64 {
+		values := map[string]float64{}
+		for i := len(e.Inputs) - 1; i >= 0; i-- {
+			values[e.Inputs[i].Name] = grid[i][n%len(grid[i])]
+			n /= len(grid[i])
+		}
+		return values
+	}
+	count, stride, size := 1, 1, 0
+	for i := len(e.Inputs) - 1; i >= 0; i-- {
+		if e.Inputs[i].Name == input {
+			stride, size = count, len(grid[i])
+		}
+		count *= len(grid[i])
+	}
+	outputs := make([]float64, count)
+	for n := range outputs {
+		output, err := e.EvaluateCrisp(values(n))
+		if err != nil && err != ErrZeroDegree {
+			return nil, err
+		}
+		outputs[n] = output
+	}
+
+	// compare every grid point to the one with the next higher value of the given input
+	var violations []MonotonicityViolation
+	for n, output := range outputs {
+		if (n/stride)%size == size-1 {
+			continue
+		}
+		difference := outputs[n+stride] - output
+		if decreasing {
+			difference = -difference
+		}
+		if difference < -tolerance-1e-9*math.Max(1, math.Abs(output)) {
+			violations = append(violations, MonotonicityViolation{values(n), values(n + stride), output, outputs[n+stride]})
+		}
+	}
+	return violations, nil
+}
@@ -12,6 +12,22 @@ func main() {
 
 	// load passwordList from file to heap for predictability calculation
 	api.SetupPasswordList()
+	// open local breach corpus (if configured) for the breach check
+	api.SetupBreachCorpus()
+	// override attack scenarios for crack time estimation (if configured)
+	api.SetupAttackScenarios()
+	// select complexity model version (if configured)
+	api.SetupComplexityModel()
+	// load leetspeak substitution table (if configured)
+	api.SetupLeetTable()
+	// restrict keyboard layouts of the distance cost model (if configured)
+	api.SetupCostModel()
+	// load alternative strength model from FCL file (if configured)
+	api.SetupFuzzyModel()
+	// select inference mode of the strength model (if configured)
+	api.SetupInference()
+	// enable explanations for admin tooling (if configured)
+	api.SetupExplainToken()
 
 	// listen on port 8000 for staging/development
 	serverPort := "8000"
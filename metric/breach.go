@@ -0,0 +1,237 @@
+package metric
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// hashLength is the length of a hex encoded SHA-1 hash.
+const hashLength = 40
+
+// lineBufferSize is the number of bytes read at once while searching the corpus.
+// It has to be greater than two lines of the corpus ("HASH:COUNT\r\n").
+const lineBufferSize = 256
+
+// BreachCorpus is the local hash corpus used by CalculateBreach.
+// If it is nil, no password is considered breached.
+var BreachCorpus *HashCorpus
+
+// HashCorpus is a local file of SHA-1 password hashes in the format of the Pwned Passwords download
+// ("HASH:COUNT" per line with an upper case hex hash, ordered by hash).
+// Lookups use a binary search directly on the file, so the corpus is never loaded into memory.
+type HashCorpus struct {
+	file *os.File
+	size int64
+}
+
+// OpenHashCorpus opens the hash corpus at the given path.
+func OpenHashCorpus(path string) (*HashCorpus, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return &HashCorpus{file: file, size: info.Size()}, nil
+}
+
+// Close closes the underlying file of the corpus.
+func (c *HashCorpus) Close() error {
+	return c.file.Close()
+}
+
+// nextLine returns the offset of the first line starting at or after offset off and the line itself
+// (without line break). If there is no such line, the returned offset equals the size of the corpus.
+func (c *HashCorpus) nextLine(off int64) (int64, string, error) {
+	buf := make([]byte, lineBufferSize)
+
+	// a line only starts at off > 0 if the previous byte is a line break, so skip to the next one
+	start := off
+	if off > 0 {
+		for pos := off - 1; ; {
+			n, err := c.file.ReadAt(buf, pos)
+			if err != nil && err != io.EOF {
+				return 0, "", err
+			}
+			if newline := bytes.IndexByte(buf[:n], '\n'); newline != -1 {
+				start = pos + int64(newline) + 1
+				break
+			}
+			if err == io.EOF {
+				return c.size, "", nil
+			}
+			pos += int64(n)
+		}
+	}
+
+	if start >= c.size {
+		return c.size, "", nil
+	}
+
+	n, err := c.file.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+	line := buf[:n]
+	if newline := bytes.IndexByte(line, '\n'); newline != -1 {
+		line = line[:newline]
+	} else if err != io.EOF {
+		return 0, "", fmt.Errorf("line at offset %d of hash corpus is longer than %d bytes", start, lineBufferSize)
+	}
+
+	return start, strings.TrimRight(string(line), "\r"), nil
+}
+
+// search returns the offset of the first line whose hash is not less than the given key.
+// The key may be a full hash or a prefix of a hash.
+func (c *HashCorpus) search(key string) (int64, error) {
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, err := c.nextLine(mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if start >= c.size || lineHash(line) >= key {
+			hi = mid
+		} else {
+			lo = start + 1
+		}
+	}
+
+	start, _, err := c.nextLine(lo)
+	return start, err
+}
+
+// lineHash returns the hash part of a corpus line.
+func lineHash(line string) string {
+	if i := strings.IndexByte(line, ':'); i != -1 {
+		return line[:i]
+	}
+	return line
+}
+
+// parseLine splits a corpus line into its hash and its count.
+func parseLine(line string) (string, int, error) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("malformed hash corpus line %q", line)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return "", 0, fmt.Errorf("malformed count in hash corpus line %q", line)
+	}
+	return parts[0], count, nil
+}
+
+// Lookup returns how often the password with the given upper case hex SHA-1 hash appeared in breaches.
+// It returns 0 if the hash is not part of the corpus.
+func (c *HashCorpus) Lookup(hash string) (int, error) {
+	if len(hash) != hashLength {
+		return 0, errors.New("Lookup received an invalid SHA-1 hash")
+	}
+
+	start, err := c.search(hash)
+	if err != nil || start >= c.size {
+		return 0, err
+	}
+
+	_, line, err := c.nextLine(start)
+	if err != nil {
+		return 0, err
+	}
+
+	lineHash, count, err := parseLine(line)
+	if err != nil || lineHash != hash {
+		return 0, err
+	}
+	return count, nil
+}
+
+// RangeEntry is a hash suffix of the corpus together with its breach count.
+type RangeEntry struct {
+	Suffix string
+	Count  int
+}
+
+// Range returns all entries of the corpus whose hash starts with the given upper case hex prefix.
+// The returned suffixes do not contain the prefix and are ordered by hash.
+func (c *HashCorpus) Range(prefix string) ([]RangeEntry, error) {
+	start, err := c.search(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// read the matching lines sequentially, starting at the first one
+	var entries []RangeEntry
+	scanner := bufio.NewScanner(io.NewSectionReader(c.file, start, c.size-start))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if !strings.HasPrefix(line, prefix) {
+			break
+		}
+
+		hash, count, err := parseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, RangeEntry{Suffix: hash[len(prefix):], Count: count})
+	}
+	return entries, scanner.Err()
+}
+
+// HashPassword returns the upper case hex SHA-1 hash of the given password as used by the hash corpus.
+func HashPassword(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// CalculateBreach calculates the breach prevalence of the given password,
+// which is the number of times it appeared in the breaches contained in BreachCorpus.
+func CalculateBreach(password string) int {
+	if BreachCorpus == nil {
+		return 0
+	}
+
+	count, err := BreachCorpus.Lookup(HashPassword(password))
+	if err != nil {
+		// an unreadable corpus must not prevent the other metrics from being calculated, but must not go unnoticed
+		log.Printf("Error: Could not look up password in breach corpus: %s\n", err)
+		return 0
+	}
+	return count
+}
+
+// GetHintBreach provides a hint if the password has appeared in a breach, otherwise an empty string is returned
+func GetHintBreach(count int, language string) string {
+	if count <= 0 {
+		return ""
+	}
+
+	if language == "de" {
+		if count == 1 {
+			return "Dein Passwort ist bereits in einem Datenleck aufgetaucht. Verwende es nicht!"
+		}
+		return fmt.Sprintf("Dein Passwort ist bereits %d-mal in Datenlecks aufgetaucht. Verwende es nicht!", count)
+	}
+	if count == 1 {
+		return "Your password has appeared in a data breach before. Do not use it!"
+	}
+	return fmt.Sprintf("Your password has appeared %d times in data breaches before. Do not use it!", count)
+}
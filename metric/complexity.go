@@ -1,10 +1,31 @@
 package metric
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"unicode"
 )
 
+// Versions of the complexity model.
+const (
+	// ComplexityModelV1 weights every char by the size of its character set, so complexity grows with the length.
+	ComplexityModelV1 = "v1"
+	// ComplexityModelV2 combines the size of the character pool with the diversity of the chars, independent of the length.
+	ComplexityModelV2 = "v2"
+)
+
+// ComplexityModel is the version of the complexity model used by CalculateComplexity.
+var ComplexityModel = ComplexityModelV1
+
+// maxComplexity is the complexity of a very complex password for each version of the complexity model.
+var maxComplexity = map[string]float64{ComplexityModelV1: 677, ComplexityModelV2: 100}
+
+// MaxComplexity returns the complexity of a very complex password in the current ComplexityModel.
+func MaxComplexity() float64 {
+	return maxComplexity[ComplexityModel]
+}
+
 // numberOfChars calculates the number of characters
 func numberOfChars(pw string) (int, int, int, int) {
 	var low = 0
@@ -33,9 +54,18 @@ func numberOfChars(pw string) (int, int, int, int) {
 	return low, up, d, special
 }
 
-// CalculateComplexity calculates the complexity for a given password string.
+// CalculateComplexity calculates the complexity for a given password string using the current ComplexityModel.
 // It returns the complexity as float64.
 func CalculateComplexity(pw string) float64 {
+	if ComplexityModel == ComplexityModelV2 {
+		return CalculateComplexityV2(pw)
+	}
+	return CalculateComplexityV1(pw)
+}
+
+// CalculateComplexityV1 calculates the complexity for a given password string by weighting every char with the size of its character set.
+// It returns the complexity as float64.
+func CalculateComplexityV1(pw string) float64 {
 	low, up, d, special := numberOfChars(pw)
 
 	complexity := low*26 + up*26 + d*10 + special*33
@@ -58,8 +88,145 @@ func ComplexityReward(c int, n int, m int, p int, q int) float64 {
 	return float64(c) * 0.25 * count
 }
 
+// characterPool returns the number of chars of all character sets (lowercase, uppercase, digits, special) used in the password.
+func characterPool(low int, up int, d int, special int) int {
+	pool := 0
+	for i, count := range [4]int{low, up, d, special} {
+		if count > 0 {
+			pool += [4]int{26, 26, 10, 33}[i]
+		}
+	}
+	return pool
+}
+
+// minDiversityLength is the number of chars a password needs to be fully diverse,
+// as a shorter password can not show whether its chars would repeat.
+const minDiversityLength = 8
+
+// CalculateDiversity calculates how diverse the chars of a given password are.
+// It returns the mean of the ratio of unique chars and the Shannon entropy of the char distribution
+// relative to their maximum (every char unique) for at least minDiversityLength chars, both between 0 and 1.
+func CalculateDiversity(pw string) float64 {
+	runes := []rune(pw)
+	if len(runes) == 0 {
+		return 0
+	}
+	length := math.Max(float64(len(runes)), minDiversityLength)
+
+	frequencies := map[rune]int{}
+	for _, r := range runes {
+		frequencies[r]++
+	}
+
+	entropy := 0.0
+	for _, frequency := range frequencies {
+		p := float64(frequency) / float64(len(runes))
+		entropy -= p * math.Log2(p)
+	}
+
+	uniqueRatio := float64(len(frequencies)) / length
+	return (uniqueRatio + entropy/math.Log2(length)) / 2
+}
+
+// CalculateComplexityV2 calculates the complexity for a given password string based on the size of its character pool
+// (log2 of the pool relative to the one of all character sets) and the diversity of its chars (see CalculateDiversity).
+// It returns the complexity between 0 and 100 as float64.
+func CalculateComplexityV2(pw string) float64 {
+	low, up, d, special := numberOfChars(pw)
+	pool := characterPool(low, up, d, special)
+	if low < 0 || pool == 0 {
+		return 0
+	}
+
+	return complexityV2(pw, pool)
+}
+
+// complexityV2 returns the complexity of ComplexityModelV2 for given password and size of its character pool.
+func complexityV2(pw string, pool int) float64 {
+	poolFactor := math.Log2(float64(pool)) / math.Log2(float64(characterPool(1, 1, 1, 1)))
+	return 100 * poolFactor * CalculateDiversity(pw)
+}
+
+// CalculateStructuralComplexity calculates the complexity for a given password and its structure using the current ComplexityModel.
+// If the structure is predictable, uppercase letters only placed first or everywhere count as lowercase letters and
+// digits and special characters only appended at the end do not count as additional character sets.
+func CalculateStructuralComplexity(pw string, structure Structure) float64 {
+	if !structure.Predictable() {
+		return CalculateComplexity(pw)
+	}
+
+	low, up, d, special := numberOfChars(pw)
+	if low < 0 {
+		return CalculateComplexity(pw)
+	}
+
+	// counts of the character sets as they count for the reward or pool
+	rewardLow, rewardUp, rewardD, rewardSpecial := low, up, d, special
+	if structure.CapitalFirst || structure.AllCaps {
+		rewardLow, rewardUp = low+up, 0
+	}
+	if structure.DigitSuffix {
+		rewardD = 0
+	}
+	if structure.SymbolSuffix {
+		rewardSpecial = 0
+	}
+
+	if ComplexityModel == ComplexityModelV2 {
+		pool := characterPool(rewardLow, rewardUp, rewardD, rewardSpecial)
+		if pool == 0 {
+			return 0
+		}
+		return complexityV2(pw, pool)
+	}
+
+	complexity := low*26 + up*26 + d*10 + special*33
+	return ComplexityReward(complexity, rewardLow, rewardUp, rewardD, rewardSpecial)
+}
+
+// complexityV1Peaks are the peaks of the membership functions of the complexity of ComplexityModelV1 and
+// complexityV2Peaks the ones of ComplexityModelV2 (see fuzzy.ComplexityVariable and fuzzy.ComplexityV2Variable).
+var complexityV1Peaks, complexityV2Peaks = []float64{5, 173, 341, 509, 677}, []float64{45, 65, 79, 85, 95}
+
+// complexityV1Scale converts a complexity of the current ComplexityModel to the scale of ComplexityModelV1
+// by mapping the peaks of the membership functions of both models onto each other, linearly in between.
+func complexityV1Scale(complexity float64) float64 {
+	if ComplexityModel != ComplexityModelV2 {
+		return complexity
+	}
+
+	if complexity <= complexityV2Peaks[0] {
+		return complexity * complexityV1Peaks[0] / complexityV2Peaks[0]
+	}
+	for i := 1; i < len(complexityV2Peaks); i++ {
+		if complexity <= complexityV2Peaks[i] {
+			lower, upper := complexityV2Peaks[i-1], complexityV2Peaks[i]
+			return complexityV1Peaks[i-1] + (complexity-lower)*(complexityV1Peaks[i]-complexityV1Peaks[i-1])/(upper-lower)
+		}
+	}
+	return complexityV1Peaks[len(complexityV1Peaks)-1]
+}
+
+// GetHintDiversity provides a hint if the password consists of only few different chars.
+// It returns an empty string if the password is diverse enough or ComplexityModelV1 is used, which does not consider the diversity.
+func GetHintDiversity(pw string, language string) string {
+	if ComplexityModel != ComplexityModelV2 || pw == "" || CalculateDiversity(pw) >= 0.5 {
+		return ""
+	}
+
+	unique := map[rune]bool{}
+	for _, r := range pw {
+		unique[r] = true
+	}
+	if language == "de" {
+		return fmt.Sprintf("Dein Passwort verwendet nur %d verschiedene Zeichen.", len(unique))
+	}
+	return fmt.Sprintf("Your password uses only %d different characters.", len(unique))
+}
+
 // GetHintComplexity provides a hint for the metric complexity based on the password and its complexity
 func GetHintComplexity(pw string, complexity float64, language string) string {
+	complexity = complexityV1Scale(complexity)
 	if language == "de" {
 		if complexity > 543.0 { // exactly between complex and very complex, no hint necessary
 			return "Die Komplexität deines Passwortes ist sehr gut."
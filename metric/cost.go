@@ -0,0 +1,128 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+)
+
+// CostModel provides the costs of the edit operations of the distance used by predictability.
+// Edits transform the password (a) into an entry of PasswordList (b).
+type CostModel interface {
+	// Insertion returns the cost of a char r of b missing in a.
+	Insertion(r rune) float64
+	// Deletion returns the cost of a char r of a missing in b.
+	Deletion(r rune) float64
+	// Substitution returns the cost of a char r of a written as char s in b.
+	Substitution(r, s rune) float64
+	// Transposition returns the cost of the adjacent chars r and s of a swapped in b.
+	Transposition(r, s rune) float64
+	// MinInsertionDeletion returns a lower bound of the cost of any insertion or deletion.
+	MinInsertionDeletion() float64
+}
+
+// LevenshteinCostModel is the classic cost model of predictability: insertions and deletions cost 1, substitutions
+// cost 1 if only the case differs and 2 otherwise. Transpositions are not supported.
+type LevenshteinCostModel struct{}
+
+// Insertion returns 1.
+func (LevenshteinCostModel) Insertion(r rune) float64 {
+	return 1
+}
+
+// Deletion returns 1.
+func (LevenshteinCostModel) Deletion(r rune) float64 {
+	return 1
+}
+
+// Substitution returns 0 for equal chars, 1 for chars only differing in case and 2 otherwise.
+func (LevenshteinCostModel) Substitution(r, s rune) float64 {
+	if r == s {
+		// same char -> change distance is 0
+		return 0
+	} else if unicode.ToLower(r) == unicode.ToLower(s) {
+		// char just up/down shifted -> change distance is 1
+		return 1
+	}
+	// char is completely different -> change distance is 2
+	return 2
+}
+
+// Transposition returns infinity, as transpositions are not supported.
+func (LevenshteinCostModel) Transposition(r, s rune) float64 {
+	return math.Inf(1)
+}
+
+// MinInsertionDeletion returns 1.
+func (LevenshteinCostModel) MinInsertionDeletion() float64 {
+	return 1
+}
+
+// KeyboardCostModel extends LevenshteinCostModel by transpositions of adjacent chars (e.g. "passowrd")
+// and cheaper substitutions of chars on neighbouring keys of a keyboard (e.g. "paasword").
+type KeyboardCostModel struct {
+	LevenshteinCostModel
+	// AdjacentCost is the cost of substituting a char by one on a neighbouring key.
+	AdjacentCost float64
+	// TranspositionCost is the cost of swapping two adjacent chars.
+	TranspositionCost float64
+	// neighbors contains every pair of lowercase chars on neighbouring keys of the layouts of the model.
+	neighbors map[[2]rune]bool
+}
+
+// NewKeyboardCostModel returns a KeyboardCostModel for the given names of KeyboardLayouts (all layouts if none are given).
+// It returns an error if a layout is unknown.
+func NewKeyboardCostModel(layouts ...string) (*KeyboardCostModel, error) {
+	if len(layouts) == 0 {
+		for _, layout := range KeyboardLayouts {
+			layouts = append(layouts, layout.Name)
+		}
+	}
+
+	neighbors := map[[2]rune]bool{}
+	for _, name := range layouts {
+		found := false
+		for _, graph := range keyboardGraphs {
+			if graph.layout.Name != name {
+				continue
+			}
+			found = true
+			for r, adjacent := range graph.neighbors {
+				for s := range adjacent {
+					if unicode.ToLower(r) != unicode.ToLower(s) {
+						neighbors[[2]rune{unicode.ToLower(r), unicode.ToLower(s)}] = true
+					}
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown keyboard layout %s", name)
+		}
+	}
+
+	return &KeyboardCostModel{AdjacentCost: 1.5, TranspositionCost: 1, neighbors: neighbors}, nil
+}
+
+// Substitution returns AdjacentCost for chars on neighbouring keys (regardless of their case)
+// and the cost of LevenshteinCostModel otherwise.
+func (m *KeyboardCostModel) Substitution(r, s rune) float64 {
+	cost := m.LevenshteinCostModel.Substitution(r, s)
+	if cost > m.AdjacentCost && m.neighbors[[2]rune{unicode.ToLower(r), unicode.ToLower(s)}] {
+		return m.AdjacentCost
+	}
+	return cost
+}
+
+// Transposition returns TranspositionCost.
+func (m *KeyboardCostModel) Transposition(r, s rune) float64 {
+	return m.TranspositionCost
+}
+
+// DistanceCostModel is the cost model of the distance used by predictability.
+var DistanceCostModel CostModel = func() CostModel {
+	model, err := NewKeyboardCostModel()
+	if err != nil {
+		panic(err)
+	}
+	return model
+}()
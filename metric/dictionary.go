@@ -0,0 +1,164 @@
+package metric
+
+import (
+	"sync"
+	"unicode"
+)
+
+// minWordLength is the minimal length of a dictionary word, shorter entries of PasswordList are not used as words.
+const minWordLength = 3
+
+// trieNode is a node of the dictionary trie, built from the lowercase letter-only entries of PasswordList.
+// rank is the (1-based) position of the word ending at this node in PasswordList, or 0 if no word ends here.
+type trieNode struct {
+	children []trieEdge
+	rank     int
+}
+
+// trieEdge is an edge of the dictionary trie labelled with a lowercase letter.
+type trieEdge struct {
+	char rune
+	node *trieNode
+}
+
+// child returns the child of n for the given char, or nil if there is none.
+func (n *trieNode) child(char rune) *trieNode {
+	for _, edge := range n.children {
+		if edge.char == char {
+			return edge.node
+		}
+	}
+	return nil
+}
+
+// insert adds the given word with its rank to the trie below n, keeping the lowest rank of duplicates.
+func (n *trieNode) insert(word []rune, rank int) {
+	node := n
+	for _, char := range word {
+		next := node.child(char)
+		if next == nil {
+			next = &trieNode{}
+			node.children = append(node.children, trieEdge{char, next})
+		}
+		node = next
+	}
+	if node.rank == 0 || rank < node.rank {
+		node.rank = rank
+	}
+}
+
+// dictionaryMutex guards dictionary, passwordRanks and dictionaryList, as they are built lazily by concurrent requests.
+var dictionaryMutex sync.Mutex
+
+// dictionary is the root of the dictionary trie.
+var dictionary *trieNode
+
+// passwordRanks maps every entry of PasswordList to its (1-based) rank, keeping the lowest rank of duplicates.
+var passwordRanks map[string]int
+
+// dictionaryList is PasswordList when dictionary and passwordRanks were built.
+var dictionaryList [][]rune
+
+// isPasswordList returns whether the given list is (still) PasswordList, i.e. it shares its entries and has its length.
+func isPasswordList(list [][]rune) bool {
+	return len(list) == len(PasswordList) && (len(list) == 0 || &list[0] == &PasswordList[0])
+}
+
+// getDictionary returns the dictionary trie of PasswordList. It is (re)built on first use and whenever PasswordList changed.
+func getDictionary() *trieNode {
+	dictionaryMutex.Lock()
+	defer dictionaryMutex.Unlock()
+
+	buildDictionary()
+	return dictionary
+}
+
+// getPasswordRanks returns the ranks of all entries of PasswordList.
+// It is (re)built on first use and whenever PasswordList changed.
+func getPasswordRanks() map[string]int {
+	dictionaryMutex.Lock()
+	defer dictionaryMutex.Unlock()
+
+	buildDictionary()
+	return passwordRanks
+}
+
+// buildDictionary builds dictionary and passwordRanks if PasswordList changed since they were built last.
+// dictionaryMutex must be held by the caller.
+func buildDictionary() {
+	if dictionary != nil && isPasswordList(dictionaryList) {
+		return
+	}
+
+	root := &trieNode{}
+	ranks := make(map[string]int, len(PasswordList))
+	for i, entry := range PasswordList {
+		if _, ok := ranks[string(entry)]; !ok {
+			ranks[string(entry)] = i + 1
+		}
+
+		if len(entry) < minWordLength {
+			continue
+		}
+
+		word := make([]rune, len(entry))
+		isWord := true
+		for j, char := range entry {
+			if !unicode.IsLetter(char) {
+				isWord = false
+				break
+			}
+			word[j] = unicode.ToLower(char)
+		}
+		if isWord {
+			root.insert(word, i+1)
+		}
+	}
+
+	dictionary, passwordRanks, dictionaryList = root, ranks, PasswordList
+}
+
+// dictionaryMatch is a dictionary word found in a password.
+// end is the (exclusive) end index of the word in the password, leet is the number of leet substituted chars.
+type dictionaryMatch struct {
+	end  int
+	word string
+	rank int
+	leet int
+}
+
+// findDictionaryWords returns all dictionary words starting at index start of the password, whose leet tokens are
+// given by leetTokensStartingAt. Matching is case insensitive and leet aware (see leetTable), e.g. "Dr4gon" matches
+// the word "dragon".
+func findDictionaryWords(password []rune, leetTokens [][]leetToken, start int) []dictionaryMatch {
+	var matches []dictionaryMatch
+
+	var walk func(node *trieNode, pos int, word []rune, leet int)
+	walk = func(node *trieNode, pos int, word []rune, leet int) {
+		if node.rank != 0 && pos-start >= minWordLength {
+			matches = append(matches, dictionaryMatch{end: pos, word: string(word), rank: node.rank, leet: leet})
+		}
+		if pos >= len(password) {
+			return
+		}
+
+		char := unicode.ToLower(password[pos])
+		if next := node.child(char); next != nil {
+			walk(next, pos+1, append(word, char), leet)
+		}
+		for _, token := range leetTokens[pos] {
+			next := node
+			for _, letter := range token.other {
+				if next = next.child(letter); next == nil {
+					break
+				}
+			}
+			if next != nil {
+				walk(next, pos+token.length, append(word, token.other...), leet+1)
+			}
+		}
+	}
+	walk(getDictionary(), start, make([]rune, 0, len(password)-start), 0)
+
+	return matches
+}
@@ -0,0 +1,172 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Names of the default attack scenarios.
+const (
+	// ScenarioOnlineThrottled is an online attack against a service limiting the number of login attempts.
+	ScenarioOnlineThrottled = "online-throttled"
+	// ScenarioOnlineUnthrottled is an online attack against a service without rate limiting.
+	ScenarioOnlineUnthrottled = "online-unthrottled"
+	// ScenarioOfflineBcrypt is an offline attack on a stolen hash of a slow hash function like bcrypt.
+	ScenarioOfflineBcrypt = "offline-bcrypt"
+	// ScenarioOfflineFastHash is an offline attack on a stolen hash of a fast hash function like SHA-1 or MD5.
+	ScenarioOfflineFastHash = "offline-fast-hash"
+)
+
+// AttackScenario is a scenario an attacker guesses passwords in, given by the number of guesses per second.
+type AttackScenario struct {
+	Name             string
+	GuessesPerSecond float64
+}
+
+// AttackScenarios are the scenarios crack times are estimated for. They can be changed or extended.
+var AttackScenarios = []AttackScenario{
+	{Name: ScenarioOnlineThrottled, GuessesPerSecond: 100.0 / 3600},
+	{Name: ScenarioOnlineUnthrottled, GuessesPerSecond: 10},
+	{Name: ScenarioOfflineBcrypt, GuessesPerSecond: 1e4},
+	{Name: ScenarioOfflineFastHash, GuessesPerSecond: 1e10},
+}
+
+// scenarioNames are the localised names (en, de) of the default attack scenarios used in hints.
+var scenarioNames = map[string][2]string{
+	ScenarioOnlineThrottled:   {"online with rate limiting", "online mit Begrenzung"},
+	ScenarioOnlineUnthrottled: {"online without rate limiting", "online ohne Begrenzung"},
+	ScenarioOfflineBcrypt:     {"offline against bcrypt", "offline gegen bcrypt"},
+	ScenarioOfflineFastHash:   {"offline against a fast hash", "offline gegen einen schnellen Hash"},
+}
+
+// CrackTime is the estimated time (in seconds) needed to guess a password in an attack scenario.
+type CrackTime struct {
+	Scenario string
+	Seconds  float64
+}
+
+// bruteForceGuesses returns the size of the search space of a brute force attack on the password,
+// based on the character sets (lowercase, uppercase, digits, special) used.
+func bruteForceGuesses(password []rune) float64 {
+	var lower, upper, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsNumber(r):
+			digit = true
+		default:
+			special = true
+		}
+	}
+
+	pool := 0.0
+	for _, set := range []struct {
+		used bool
+		size float64
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {special, 33}} {
+		if set.used {
+			pool += set.size
+		}
+	}
+	return math.Pow(pool, float64(len(password)))
+}
+
+// EstimateGuesses estimates the number of guesses an attacker needs for the given password and its segmentation.
+// It is the minimum of the rank of the password in PasswordList, the guesses needed for its segmentation
+// (dictionary words, keyboard walks and other patterns) and the size of the brute force search space.
+func EstimateGuesses(password string, segmentation Segmentation) float64 {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 1
+	}
+
+	guesses := math.Min(math.Pow(2, segmentation.Entropy), bruteForceGuesses(runes))
+	if rank, ok := getPasswordRanks()[password]; ok {
+		guesses = math.Min(guesses, float64(rank))
+	}
+	return math.Max(guesses, 1)
+}
+
+// CalculateCrackTimes calculates the crack times of a password needing the given number of guesses for all AttackScenarios.
+// On average, an attacker has to try half of the guesses.
+func CalculateCrackTimes(guesses float64) []CrackTime {
+	crackTimes := make([]CrackTime, len(AttackScenarios))
+	for i, scenario := range AttackScenarios {
+		crackTimes[i] = CrackTime{Scenario: scenario.Name, Seconds: guesses / 2 / scenario.GuessesPerSecond}
+	}
+	return crackTimes
+}
+
+// timeUnits are the units (in seconds) used to display durations, with their localised names (singular and plural in en and de).
+var timeUnits = []struct {
+	seconds float64
+	names   [4]string
+}{
+	{100 * 365.2425 * 24 * 3600, [4]string{"century", "centuries", "Jahrhundert", "Jahrhunderte"}},
+	{365.2425 * 24 * 3600, [4]string{"year", "years", "Jahr", "Jahre"}},
+	{365.2425 / 12 * 24 * 3600, [4]string{"month", "months", "Monat", "Monate"}},
+	{24 * 3600, [4]string{"day", "days", "Tag", "Tage"}},
+	{3600, [4]string{"hour", "hours", "Stunde", "Stunden"}},
+	{60, [4]string{"minute", "minutes", "Minute", "Minuten"}},
+	{1, [4]string{"second", "seconds", "Sekunde", "Sekunden"}},
+}
+
+// FormatDuration returns a localised, human readable representation of the given number of seconds, e.g. "3 hours".
+func FormatDuration(seconds float64, language string) string {
+	if seconds < 1 {
+		if language == "de" {
+			return "weniger als eine Sekunde"
+		}
+		return "less than a second"
+	}
+
+	for _, unit := range timeUnits {
+		if seconds < unit.seconds {
+			continue
+		}
+
+		amount := math.Floor(seconds / unit.seconds)
+		if unit.seconds == timeUnits[0].seconds && amount >= 10 {
+			if language == "de" {
+				return "viele Jahrhunderte"
+			}
+			return "many centuries"
+		}
+
+		offset := 0
+		if language == "de" {
+			offset = 2
+		}
+		if amount == 1 {
+			return fmt.Sprintf("1 %s", unit.names[offset])
+		}
+		return fmt.Sprintf("%.0f %s", amount, unit.names[offset+1])
+	}
+	return ""
+}
+
+// GetHintGuesses provides a hint with the estimated number of guesses and the crack times in all attack scenarios.
+func GetHintGuesses(guesses float64, crackTimes []CrackTime, language string) string {
+	parts := make([]string, len(crackTimes))
+	for i, crackTime := range crackTimes {
+		scenario := crackTime.Scenario
+		if names, ok := scenarioNames[scenario]; ok {
+			scenario = names[0]
+			if language == "de" {
+				scenario = names[1]
+			}
+		}
+		parts[i] = fmt.Sprintf("%s %s", FormatDuration(crackTime.Seconds, language), scenario)
+	}
+
+	exponent := int(math.Floor(math.Log10(guesses)))
+	if language == "de" {
+		return fmt.Sprintf("Ein Angreifer bräuchte etwa 10^%d Versuche, um dein Passwort zu erraten: %s.", exponent, strings.Join(parts, ", "))
+	}
+	return fmt.Sprintf("An attacker would need about 10^%d guesses to find your password: %s.", exponent, strings.Join(parts, ", "))
+}
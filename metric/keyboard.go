@@ -0,0 +1,270 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+)
+
+// Kinds of a KeyboardWalk.
+const (
+	// WalkStraight is a walk along a row of the keyboard.
+	WalkStraight = "straight"
+	// WalkDiagonal is a walk along a (slanted) column of the keyboard.
+	WalkDiagonal = "diagonal"
+	// WalkSnake is a walk changing its direction at least once.
+	WalkSnake = "snake"
+)
+
+// minKeyboardLength is the minimal length of a keyboard walk.
+const minKeyboardLength = 3
+
+// KeyboardLayout describes a keyboard by its rows of unshifted and shifted keys (from top to bottom)
+// and the horizontal position of the centre of the first key of each row (in key widths).
+type KeyboardLayout struct {
+	Name        string
+	Rows        []string
+	ShiftedRows []string
+	RowOffsets  []float64
+}
+
+// KeyboardLayouts are the layouts keyboard walks are detected on.
+var KeyboardLayouts = []KeyboardLayout{
+	{
+		Name:        "QWERTY",
+		Rows:        []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"},
+		ShiftedRows: []string{"~!@#$%^&*()_+", "QWERTYUIOP{}|", "ASDFGHJKL:\"", "ZXCVBNM<>?"},
+		RowOffsets:  []float64{0.5, 2, 2.25, 2.75},
+	},
+	{
+		Name:        "QWERTZ",
+		Rows:        []string{"^1234567890ß´", "qwertzuiopü+", "asdfghjklöä#", "<yxcvbnm,.-"},
+		ShiftedRows: []string{"°!\"§$%&/()=?`", "QWERTZUIOPÜ*", "ASDFGHJKLÖÄ'", ">YXCVBNM;:_"},
+		RowOffsets:  []float64{0.5, 2, 2.25, 1.75},
+	},
+	{
+		Name:        "AZERTY",
+		Rows:        []string{"²&é\"'(-è_çà)=", "azertyuiop^$", "qsdfghjklmù*", "<wxcvbn,;:!"},
+		ShiftedRows: []string{"²1234567890°+", "AZERTYUIOP¨£", "QSDFGHJKLM%µ", ">WXCVBN?./§"},
+		RowOffsets:  []float64{0.5, 2, 2.25, 1.75},
+	},
+}
+
+// keyPosition is the position of a key on a keyboard layout.
+type keyPosition struct {
+	row     int
+	x       float64
+	shifted bool
+}
+
+// direction is the direction of a step between two neighbouring keys:
+// rows is the change of the row (-1: up, 0: same row, 1: down) and columns the horizontal change (-1: left, 1: right).
+type direction struct {
+	rows    int
+	columns int
+}
+
+// keyboardGraph is the adjacency graph of a keyboard layout.
+// It maps every char to its neighbouring chars and the direction of the step towards them.
+type keyboardGraph struct {
+	layout    KeyboardLayout
+	positions map[rune]keyPosition
+	neighbors map[rune]map[rune]direction
+	degree    float64
+}
+
+// newKeyboardGraph builds the adjacency graph of given layout. Two keys are neighbours if they are next to each
+// other in a row or if they are in adjacent rows and their centres are less than one key width apart.
+// Shifted chars are neighbours of the same keys as their unshifted counterparts.
+func newKeyboardGraph(layout KeyboardLayout) keyboardGraph {
+	positions := map[rune]keyPosition{}
+	for row := range layout.Rows {
+		for _, keys := range []struct {
+			chars   string
+			shifted bool
+		}{{layout.Rows[row], false}, {layout.ShiftedRows[row], true}} {
+			for column, char := range []rune(keys.chars) {
+				if _, ok := positions[char]; !ok {
+					positions[char] = keyPosition{row: row, x: layout.RowOffsets[row] + float64(column), shifted: keys.shifted}
+				}
+			}
+		}
+	}
+
+	neighbors := map[rune]map[rune]direction{}
+	edges := 0
+	for a, positionA := range positions {
+		neighbors[a] = map[rune]direction{}
+		for b, positionB := range positions {
+			rows := positionB.row - positionA.row
+			dx := positionB.x - positionA.x
+			sameRow := rows == 0 && math.Abs(dx) == 1
+			adjacentRow := (rows == -1 || rows == 1) && math.Abs(dx) < 1
+			if !sameRow && !adjacentRow {
+				continue
+			}
+
+			columns := 1
+			if dx < 0 {
+				columns = -1
+			}
+			neighbors[a][b] = direction{rows, columns}
+			edges++
+		}
+	}
+
+	return keyboardGraph{layout: layout, positions: positions, neighbors: neighbors, degree: float64(edges) / float64(len(positions))}
+}
+
+// keyboardGraphs are the adjacency graphs of KeyboardLayouts.
+var keyboardGraphs = func() []keyboardGraph {
+	graphs := make([]keyboardGraph, len(KeyboardLayouts))
+	for i, layout := range KeyboardLayouts {
+		graphs[i] = newKeyboardGraph(layout)
+	}
+	return graphs
+}()
+
+// KeyboardWalk is a part of a password typed by walking over neighbouring keys of a keyboard.
+// Start and End are the rune indices of the walk in the password (End is exclusive), Turns is the number
+// of direction changes, Shifted the number of chars typed using the shift key and Entropy the number of bits
+// an attacker needs to guess the walk.
+type KeyboardWalk struct {
+	Start   int
+	End     int
+	Token   string
+	Layout  string
+	Kind    string
+	Turns   int
+	Shifted int
+	Entropy float64
+}
+
+// walkEntropy returns the entropy (in bits) of given walk: an attacker has to guess the layout, starting key,
+// first direction, length, positions and directions of turns and the shifted keys.
+func (g keyboardGraph) walkEntropy(walk KeyboardWalk) float64 {
+	length := walk.End - walk.Start
+	entropy := math.Log2(float64(len(KeyboardLayouts))) + math.Log2(float64(len(g.positions))) + math.Log2(g.degree) + math.Log2(float64(length))
+	entropy += math.Log2(binomial(length-1, walk.Turns)) + float64(walk.Turns)*math.Log2(g.degree)
+
+	switch {
+	case walk.Shifted == length:
+		entropy++
+	case walk.Shifted > 0:
+		entropy += math.Log2(binomial(length, walk.Shifted))
+	}
+	return entropy
+}
+
+// findWalk returns the longest keyboard walk on given graph starting at index start of the password.
+// ok is false if the walk is shorter than minKeyboardLength.
+func (g keyboardGraph) findWalk(password []rune, start int) (walk KeyboardWalk, ok bool) {
+	if _, ok := g.positions[password[start]]; !ok {
+		return walk, false
+	}
+
+	walk = KeyboardWalk{Start: start, Layout: g.layout.Name, Kind: WalkStraight}
+	if g.positions[password[start]].shifted {
+		walk.Shifted++
+	}
+
+	var last direction
+	end := start + 1
+	for ; end < len(password); end++ {
+		step, ok := g.neighbors[password[end-1]][password[end]]
+		if !ok {
+			break
+		}
+
+		if end-start == 1 {
+			if step.rows != 0 {
+				walk.Kind = WalkDiagonal
+			}
+		} else if step != last {
+			walk.Turns++
+			walk.Kind = WalkSnake
+		}
+		last = step
+
+		if g.positions[password[end]].shifted {
+			walk.Shifted++
+		}
+	}
+
+	walk.End = end
+	walk.Token = string(password[start:end])
+	walk.Entropy = g.walkEntropy(walk)
+	return walk, end-start >= minKeyboardLength
+}
+
+// CalculateKeyboardWalks returns all keyboard walks (on any of KeyboardLayouts) contained in the given password.
+// For every start index only the longest walk of each layout is returned.
+func CalculateKeyboardWalks(password string) []KeyboardWalk {
+	runes := []rune(password)
+
+	var walks []KeyboardWalk
+	for _, graph := range keyboardGraphs {
+		for start := range runes {
+			if walk, ok := graph.findWalk(runes, start); ok {
+				walks = append(walks, walk)
+			}
+		}
+	}
+	return walks
+}
+
+// keyboardMatches returns the given keyboard walks as matches.
+func keyboardMatches(walks []KeyboardWalk) []Match {
+	var matches []Match
+	for _, walk := range walks {
+		matches = append(matches, Match{
+			Pattern: PatternKeyboard,
+			Start:   walk.Start,
+			End:     walk.End,
+			Token:   walk.Token,
+			Word:    walk.Layout,
+			Entropy: walk.Entropy,
+		})
+	}
+	return matches
+}
+
+// GetHintKeyboard provides a hint naming the longest of the given keyboard walks
+// (preferring fewer turns and earlier walks for walks of equal length).
+// No hint is provided for walks shorter than four chars.
+func GetHintKeyboard(walks []KeyboardWalk, language string) string {
+	var longest KeyboardWalk
+	for _, walk := range walks {
+		length := walk.End - walk.Start
+		longestLength := longest.End - longest.Start
+		if length > longestLength || length == longestLength && (walk.Turns < longest.Turns || walk.Turns == longest.Turns && walk.Start < longest.Start) {
+			longest = walk
+		}
+	}
+	if longest.End-longest.Start < 4 {
+		return ""
+	}
+
+	if language == "de" {
+		switch longest.Kind {
+		case WalkStraight:
+			return fmt.Sprintf("'%s' ist eine gerade Tastenfolge auf einer %s-Tastatur.", longest.Token, longest.Layout)
+		case WalkDiagonal:
+			return fmt.Sprintf("'%s' ist eine diagonale Tastenfolge auf einer %s-Tastatur.", longest.Token, longest.Layout)
+		}
+		if longest.Turns == 1 {
+			return fmt.Sprintf("'%s' ist eine Tastenfolge mit einem Richtungswechsel auf einer %s-Tastatur.", longest.Token, longest.Layout)
+		}
+		return fmt.Sprintf("'%s' ist eine Tastenfolge mit %d Richtungswechseln auf einer %s-Tastatur.", longest.Token, longest.Turns, longest.Layout)
+	}
+
+	switch longest.Kind {
+	case WalkStraight:
+		return fmt.Sprintf("'%s' is a straight walk on a %s keyboard.", longest.Token, longest.Layout)
+	case WalkDiagonal:
+		return fmt.Sprintf("'%s' is a diagonal walk on a %s keyboard.", longest.Token, longest.Layout)
+	}
+	if longest.Turns == 1 {
+		return fmt.Sprintf("'%s' is a walk with one turn on a %s keyboard.", longest.Token, longest.Layout)
+	}
+	return fmt.Sprintf("'%s' is a walk with %d turns on a %s keyboard.", longest.Token, longest.Turns, longest.Layout)
+}
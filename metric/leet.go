@@ -0,0 +1,242 @@
+package metric
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LeetSubstitution is a substitution of leetspeak: the letters Plain written as the token Leet (e.g. "h" as "|-|" or
+// "f" as "ph"). Cost is the edit cost of the substitution in the distance of predictability.
+type LeetSubstitution struct {
+	Plain string
+	Leet  string
+	Cost  float64
+}
+
+// DefaultLeetTable is the leetspeak substitution table used unless another one is loaded (see SetLeetTable).
+// Substitutions of a single char cost as much as changing the case of a char, longer tokens a bit more.
+var DefaultLeetTable = []LeetSubstitution{
+	{"a", "4", 1}, {"a", "@", 1}, {"a", "/-\\", 1.5}, {"a", "/\\", 1.5}, {"a", "^", 1.5},
+	{"b", "8", 1}, {"b", "|3", 1.5}, {"b", "13", 1.5},
+	{"c", "(", 1}, {"c", "{", 1}, {"c", "[", 1}, {"c", "<", 1},
+	{"d", "|)", 1.5}, {"d", "|>", 1.5},
+	{"e", "3", 1}, {"e", "€", 1.5},
+	{"f", "ph", 1}, {"f", "|=", 1.5},
+	{"g", "6", 1}, {"g", "9", 1},
+	{"h", "#", 1.5}, {"h", "|-|", 1.5}, {"h", "]-[", 1.5}, {"h", "}{", 1.5},
+	{"i", "1", 1}, {"i", "!", 1}, {"i", "|", 1},
+	{"j", "_|", 1.5},
+	{"k", "|<", 1.5}, {"k", "|{", 1.5},
+	{"l", "1", 1}, {"l", "|", 1}, {"l", "7", 1}, {"l", "|_", 1.5},
+	{"m", "|\\/|", 1.5}, {"m", "/\\/\\", 1.5},
+	{"n", "|\\|", 1.5}, {"n", "/\\/", 1.5},
+	{"o", "0", 1}, {"o", "()", 1.5}, {"o", "[]", 1.5},
+	{"p", "|*", 1.5}, {"p", "|o", 1.5},
+	{"q", "0_", 1.5},
+	{"r", "|2", 1.5}, {"r", "12", 1.5},
+	{"s", "$", 1}, {"s", "5", 1}, {"s", "z", 1.5},
+	{"t", "+", 1}, {"t", "7", 1},
+	{"u", "|_|", 1.5}, {"u", "(_)", 1.5},
+	{"v", "\\/", 1.5},
+	{"w", "\\/\\/", 1.5}, {"w", "vv", 1.5}, {"w", "\\^/", 1.5},
+	{"x", "%", 1}, {"x", "><", 1.5},
+	{"y", "`/", 1.5}, {"y", "¥", 1.5},
+	{"z", "2", 1}, {"z", "7_", 1.5},
+}
+
+// leetTable is the current leetspeak substitution table.
+var leetTable []LeetSubstitution
+
+// unleetMap maps every single-char leet token of leetTable to the single letters it may stand for.
+var unleetMap map[rune][]rune
+
+// leetTokenLength is the maximal length of a token (plain or leet) of leetTable.
+var leetTokenLength int
+
+// leetLengthCost is the minimal cost per char of length difference caused by a substitution of leetTable.
+// The distance of two strings is at least their length difference multiplied by leetLengthCost.
+var leetLengthCost float64
+
+func init() {
+	if err := SetLeetTable(DefaultLeetTable); err != nil {
+		panic(err)
+	}
+}
+
+// SetLeetTable validates the given leetspeak substitution table and uses it for predictability, dictionary words and
+// mangling rules. Plain and Leet are compared case insensitively. It must not be called during calculations.
+func SetLeetTable(table []LeetSubstitution) error {
+	unleet := map[rune][]rune{}
+	tokenLength, lengthCost := 1, 1.0
+	for _, substitution := range table {
+		plain, leet := []rune(strings.ToLower(substitution.Plain)), []rune(strings.ToLower(substitution.Leet))
+		if len(plain) == 0 || len(leet) == 0 || string(plain) == string(leet) {
+			return fmt.Errorf("invalid leet substitution '%s' -> '%s'", substitution.Plain, substitution.Leet)
+		}
+		if substitution.Cost <= 0 {
+			return fmt.Errorf("invalid cost %g of leet substitution '%s' -> '%s'", substitution.Cost, substitution.Plain, substitution.Leet)
+		}
+		for _, letter := range plain {
+			if !unicode.IsLetter(letter) {
+				return fmt.Errorf("leet substitution '%s' -> '%s' does not substitute letters", substitution.Plain, substitution.Leet)
+			}
+		}
+
+		if len(plain) == 1 && len(leet) == 1 {
+			unleet[leet[0]] = append(unleet[leet[0]], plain[0])
+		}
+		if len(plain) > tokenLength {
+			tokenLength = len(plain)
+		}
+		if len(leet) > tokenLength {
+			tokenLength = len(leet)
+		}
+		if difference := len(leet) - len(plain); difference != 0 {
+			lengthCost = minFloat(lengthCost, substitution.Cost/float64(abs(difference)))
+		}
+	}
+
+	leetTable = table
+	unleetMap = unleet
+	leetTokenLength = tokenLength
+	leetLengthCost = lengthCost
+	return nil
+}
+
+// minFloat returns the minimum of a and b.
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// abs returns the absolute value of a.
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// ParseLeetTable reads a leetspeak substitution table with one substitution per line, given as plain letters, leet token
+// and an optional cost (default 1) separated by tabs, e.g. "h\t|-|\t1.5". Empty lines and lines starting with '#' are ignored.
+func ParseLeetTable(reader io.Reader) ([]LeetSubstitution, error) {
+	var table []LeetSubstitution
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d of leet table: expected plain letters, leet token and optional cost separated by tabs", line)
+		}
+
+		substitution := LeetSubstitution{Plain: fields[0], Leet: fields[1], Cost: 1}
+		if len(fields) == 3 {
+			cost, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d of leet table: %v", line, err)
+			}
+			substitution.Cost = cost
+		}
+		table = append(table, substitution)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(table) == 0 {
+		return nil, errors.New("leet table is empty")
+	}
+	return table, nil
+}
+
+// LoadLeetTable reads the leetspeak substitution table of the given file (see ParseLeetTable) and sets it (see SetLeetTable).
+func LoadLeetTable(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	table, err := ParseLeetTable(file)
+	if err != nil {
+		return err
+	}
+	return SetLeetTable(table)
+}
+
+// WriteLeetTable writes the given leetspeak substitution table in the format read by ParseLeetTable.
+func WriteLeetTable(writer io.Writer, table []LeetSubstitution) error {
+	for _, substitution := range table {
+		if _, err := fmt.Fprintf(writer, "%s\t%s\t%g\n", substitution.Plain, substitution.Leet, substitution.Cost); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leetToken is a token of leetTable found in a string: length is the number of its chars in the string,
+// other the token it may be substituted with and cost the cost of that substitution.
+type leetToken struct {
+	length int
+	other  []rune
+	cost   float64
+}
+
+// hasTokenAt returns whether the lowercase runes contain the given lowercase token at index start.
+func hasTokenAt(runes []rune, start int, token []rune) bool {
+	if start < 0 || start+len(token) > len(runes) {
+		return false
+	}
+	for i, r := range token {
+		if runes[start+i] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// leetTokensEndingAt returns for every index i of the given string the tokens of leetTable (plain or leet) ending
+// right before index i, together with the token they may be substituted with.
+func leetTokensEndingAt(runes []rune) [][]leetToken {
+	lower := mapRunes(runes, unicode.ToLower)
+	tokens := make([][]leetToken, len(lower)+1)
+	for _, substitution := range leetTable {
+		plain, leet := []rune(strings.ToLower(substitution.Plain)), []rune(strings.ToLower(substitution.Leet))
+		for start := range lower {
+			if hasTokenAt(lower, start, plain) {
+				tokens[start+len(plain)] = append(tokens[start+len(plain)], leetToken{len(plain), leet, substitution.Cost})
+			}
+			if hasTokenAt(lower, start, leet) {
+				tokens[start+len(leet)] = append(tokens[start+len(leet)], leetToken{len(leet), plain, substitution.Cost})
+			}
+		}
+	}
+	return tokens
+}
+
+// leetTokensStartingAt returns for every index i of the given string the leet tokens of leetTable starting at index i,
+// together with the plain letters they may stand for.
+func leetTokensStartingAt(runes []rune) [][]leetToken {
+	lower := mapRunes(runes, unicode.ToLower)
+	tokens := make([][]leetToken, len(lower))
+	for _, substitution := range leetTable {
+		plain, leet := []rune(strings.ToLower(substitution.Plain)), []rune(strings.ToLower(substitution.Leet))
+		for start := range lower {
+			if hasTokenAt(lower, start, leet) {
+				tokens[start] = append(tokens[start], leetToken{len(leet), plain, substitution.Cost})
+			}
+		}
+	}
+	return tokens
+}
@@ -11,32 +11,52 @@ func CalculateLength(password string) int {
 
 // GetHintLength provides a hint for a given length
 func GetHintLength(length float64, language string) string {
+	if language == "de" {
+		return fmt.Sprintf("Deine Eingabe hat die Länge %v. ", int(length)) + getLengthRating(length, language)
+	}
+	return fmt.Sprintf("Your input has the length %v. ", int(length)) + getLengthRating(length, language)
+}
+
+// GetHintEffectiveLength provides a hint for a given length that only counts as the effective length of given repetition
+// because of repeats and sequences. The rating is based on the effective length.
+func GetHintEffectiveLength(length float64, repetition Repetition, language string) string {
+	repetitionHint := GetHintRepetition(repetition, language)
+	if repetitionHint == "" || repetition.EffectiveLength >= length {
+		return GetHintLength(length, language)
+	}
+
+	if language == "de" {
+		return fmt.Sprintf("Deine Eingabe hat die Länge %v. ", int(length)) + repetitionHint + " " + getLengthRating(repetition.EffectiveLength, language)
+	}
+	return fmt.Sprintf("Your input has the length %v. ", int(length)) + repetitionHint + " " + getLengthRating(repetition.EffectiveLength, language)
+}
+
+// getLengthRating returns the rating of a given length
+func getLengthRating(length float64, language string) string {
 	var message string
 	if language == "de" {
-		message = fmt.Sprintf("Deine Eingabe hat die Länge %v. ", int(length))
 		if length <= 5 {
-			message = message + "Das ist sehr schlecht."
+			message = "Das ist sehr schlecht."
 		} else if length <= 11 {
-			message = message + "Das ist schlecht."
+			message = "Das ist schlecht."
 		} else if length <= 17 {
-			message = message + "Das ist okay."
+			message = "Das ist okay."
 		} else if length <= 23 {
-			message = message + "Das ist gut."
+			message = "Das ist gut."
 		} else if length > 23 {
-			message = message + "Das ist sehr gut."
+			message = "Das ist sehr gut."
 		}
 	} else {
-		message = fmt.Sprintf("Your input has the length %v. ", int(length))
 		if length <= 5 {
-			message = message + "That's very bad."
+			message = "That's very bad."
 		} else if length <= 11 {
-			message = message + "That's bad."
+			message = "That's bad."
 		} else if length <= 17 {
-			message = message + "It's okay."
+			message = "It's okay."
 		} else if length <= 23 {
-			message = message + "That's good."
+			message = "That's good."
 		} else if length > 23 {
-			message = message + "That's very good."
+			message = "That's very good."
 		}
 	}
 	return message
@@ -0,0 +1,356 @@
+package metric
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Kinds of a ManglingRule, named like the corresponding hashcat rules.
+const (
+	// RuleCapitalise uppercases the first char and lowercases all others (hashcat "c").
+	RuleCapitalise = "capitalise"
+	// RuleUppercase uppercases all chars (hashcat "u").
+	RuleUppercase = "uppercase"
+	// RuleToggleCase toggles the case of all chars (hashcat "t").
+	RuleToggleCase = "toggle-case"
+	// RuleReverse reverses the word (hashcat "r").
+	RuleReverse = "reverse"
+	// RuleDuplicate appends the word to itself (hashcat "d").
+	RuleDuplicate = "duplicate"
+	// RuleAppend appends digits or special characters (hashcat "$X").
+	RuleAppend = "append"
+	// RulePrepend prepends digits or special characters (hashcat "^X").
+	RulePrepend = "prepend"
+	// RuleAppendYear appends a year (hashcat "$X" for each digit).
+	RuleAppendYear = "append-year"
+	// RulePrependYear prepends a year (hashcat "^X" for each digit).
+	RulePrependYear = "prepend-year"
+	// RuleLeet replaces all occurrences of a letter by a leet char (hashcat "sXY").
+	RuleLeet = "leet"
+)
+
+// maxManglingRules is the maximal length of a rule chain, maxAffixLength the maximal number of chars appended
+// or prepended by a single rule and maxManglingCandidates the maximal number of candidates tried per password.
+const maxManglingRules, maxAffixLength, maxManglingCandidates = 4, 4, 20000
+
+// ManglingRule is a rule transforming a word into a password, like the rules of password crackers (e.g. hashcat's best64).
+// Chars are the appended or prepended chars of RuleAppend, RulePrepend, RuleAppendYear and RulePrependYear
+// or the replaced letter followed by the leet char of RuleLeet.
+type ManglingRule struct {
+	Kind  string
+	Chars string
+}
+
+// Notation returns the rule in hashcat notation, e.g. "$1 $!" for appending "1!".
+func (r ManglingRule) Notation() string {
+	switch r.Kind {
+	case RuleCapitalise:
+		return "c"
+	case RuleUppercase:
+		return "u"
+	case RuleToggleCase:
+		return "t"
+	case RuleReverse:
+		return "r"
+	case RuleDuplicate:
+		return "d"
+	case RuleLeet:
+		return "s" + r.Chars
+	}
+
+	// append and prepend one char per rule, prepending in reverse order
+	chars := []rune(r.Chars)
+	notation := make([]string, len(chars))
+	for i, char := range chars {
+		if r.Kind == RulePrepend || r.Kind == RulePrependYear {
+			notation[len(chars)-1-i] = "^" + string(char)
+		} else {
+			notation[i] = "$" + string(char)
+		}
+	}
+	return strings.Join(notation, " ")
+}
+
+// Derivation is a chain of mangling rules deriving a password from an entry of PasswordList.
+// Rank is the (1-based) rank of Word in PasswordList and Rules are in the order they are applied to Word.
+type Derivation struct {
+	Word  string
+	Rank  int
+	Rules []ManglingRule
+}
+
+// Found returns whether the password could be derived from an entry of PasswordList.
+func (d Derivation) Found() bool {
+	return d.Rank > 0
+}
+
+// Notation returns the rule chain in hashcat notation, e.g. "c $1 $!".
+func (d Derivation) Notation() string {
+	notations := make([]string, len(d.Rules))
+	for i, rule := range d.Rules {
+		notations[i] = rule.Notation()
+	}
+	return strings.Join(notations, " ")
+}
+
+// Predictability returns the predictability of a derived password in percent.
+// Derivable passwords are highly predictable, every rule only makes them slightly less predictable.
+func (d Derivation) Predictability() float64 {
+	if !d.Found() {
+		return 0
+	}
+	return 100 - 5*float64(len(d.Rules))
+}
+
+// mapRunes returns the given runes with every rune mapped by f.
+func mapRunes(runes []rune, f func(rune) rune) []rune {
+	mapped := make([]rune, len(runes))
+	for i, r := range runes {
+		mapped[i] = f(r)
+	}
+	return mapped
+}
+
+// isCapitalised returns whether the first rune is uppercase and all others are not.
+func isCapitalised(runes []rune) bool {
+	if len(runes) == 0 || !unicode.IsUpper(runes[0]) {
+		return false
+	}
+	for _, r := range runes[1:] {
+		if unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// toggleCase returns the rune with toggled case.
+func toggleCase(r rune) rune {
+	if unicode.IsUpper(r) {
+		return unicode.ToLower(r)
+	}
+	return unicode.ToUpper(r)
+}
+
+// manglingStep is a word and the rule transforming it into the word it was inverted from.
+type manglingStep struct {
+	word []rune
+	rule ManglingRule
+}
+
+// invertAffix returns the inversions of appending (suffix) or prepending (prefix) digits or special characters to the word.
+func invertAffix(word []rune, suffix bool) []manglingStep {
+	kind, yearKind := RulePrepend, RulePrependYear
+	if suffix {
+		kind, yearKind = RuleAppend, RuleAppendYear
+	}
+
+	var steps []manglingStep
+	for length := 1; length <= maxAffixLength && length < len(word); length++ {
+		affix, rest := word[:length], word[length:]
+		if suffix {
+			affix, rest = word[len(word)-length:], word[:len(word)-length]
+		}
+
+		char := affix[0]
+		if suffix {
+			char = affix[length-1]
+		}
+		if unicode.IsLetter(char) {
+			break
+		}
+
+		rule := ManglingRule{Kind: kind, Chars: string(affix)}
+		if year := atoi(affix); length == 4 && isDigits(affix) && isValidYear(year) {
+			rule.Kind = yearKind
+		}
+		steps = append(steps, manglingStep{word: rest, rule: rule})
+	}
+	return steps
+}
+
+// isDigits returns whether all runes are digits.
+func isDigits(runes []rune) bool {
+	for _, r := range runes {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// invertRules returns all words the given word can be derived from by a single mangling rule, together with that rule.
+func invertRules(word []rune) []manglingStep {
+	var steps []manglingStep
+
+	lower := mapRunes(word, unicode.ToLower)
+	if isCapitalised(word) {
+		steps = append(steps, manglingStep{word: lower, rule: ManglingRule{Kind: RuleCapitalise}})
+	}
+
+	upper, letters := 0, 0
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if upper == letters && letters > 1 {
+		steps = append(steps, manglingStep{word: lower, rule: ManglingRule{Kind: RuleUppercase}})
+	}
+	if upper > 0 && upper < letters {
+		steps = append(steps, manglingStep{word: mapRunes(word, toggleCase), rule: ManglingRule{Kind: RuleToggleCase}})
+	}
+
+	reversed := make([]rune, len(word))
+	for i, r := range word {
+		reversed[len(word)-1-i] = r
+	}
+	if string(reversed) != string(word) {
+		steps = append(steps, manglingStep{word: reversed, rule: ManglingRule{Kind: RuleReverse}})
+	}
+
+	if half := len(word) / 2; len(word)%2 == 0 && half > 0 && string(word[:half]) == string(word[half:]) {
+		steps = append(steps, manglingStep{word: word[:half], rule: ManglingRule{Kind: RuleDuplicate}})
+	}
+
+	steps = append(steps, invertAffix(word, true)...)
+	steps = append(steps, invertAffix(word, false)...)
+
+	// replace all occurrences of a leet char by a letter it may stand for
+	seen := map[rune]bool{}
+	for _, char := range word {
+		if seen[char] {
+			continue
+		}
+		seen[char] = true
+
+		for _, letter := range unleetMap[char] {
+			replaced := mapRunes(word, func(r rune) rune {
+				if r == char {
+					return letter
+				}
+				return r
+			})
+			steps = append(steps, manglingStep{word: replaced, rule: ManglingRule{Kind: RuleLeet, Chars: string([]rune{letter, char})}})
+		}
+	}
+	return steps
+}
+
+// CalculateMangling tries to derive the given password from an entry of PasswordList by inverting common mangling rules
+// (capitalise, uppercase, toggle case, reverse, duplicate, append or prepend digits, special characters or years and
+// leet substitutions). It returns the derivation with the shortest rule chain (at most maxManglingRules rules),
+// preferring better ranked entries. The derivation is not found if no such chain exists.
+func CalculateMangling(password string) Derivation {
+	ranks := getPasswordRanks()
+	if rank, ok := ranks[password]; ok {
+		return Derivation{Word: password, Rank: rank}
+	}
+
+	// breadth first search from the password towards the words it can be derived from,
+	// rules are the inverted rules leading from the password to the word
+	type candidate struct {
+		word  []rune
+		rules []ManglingRule
+	}
+	visited := map[string]bool{password: true}
+	level := []candidate{{word: []rune(password)}}
+	for depth := 1; depth <= maxManglingRules && len(level) > 0; depth++ {
+		var best Derivation
+		var next []candidate
+		for _, current := range level {
+			for _, step := range invertRules(current.word) {
+				key := string(step.word)
+				if visited[key] || len(visited) >= maxManglingCandidates {
+					continue
+				}
+				visited[key] = true
+
+				rules := append([]ManglingRule{step.rule}, current.rules...)
+				if rank, ok := ranks[key]; ok && (!best.Found() || rank < best.Rank) {
+					best = Derivation{Word: key, Rank: rank, Rules: rules}
+				}
+				next = append(next, candidate{word: step.word, rules: rules})
+			}
+		}
+		if best.Found() {
+			return best
+		}
+		level = next
+	}
+	return Derivation{}
+}
+
+// describeRule returns a description (en or de) of the given mangling rule.
+func describeRule(rule ManglingRule, language string) string {
+	chars := []rune(rule.Chars)
+	if language == "de" {
+		switch rule.Kind {
+		case RuleCapitalise:
+			return "großer Anfangsbuchstabe"
+		case RuleUppercase:
+			return "alles groß"
+		case RuleToggleCase:
+			return "Groß- und Kleinschreibung vertauscht"
+		case RuleReverse:
+			return "rückwärts"
+		case RuleDuplicate:
+			return "verdoppelt"
+		case RuleAppend:
+			return fmt.Sprintf("'%s' angehängt", rule.Chars)
+		case RulePrepend:
+			return fmt.Sprintf("'%s' vorangestellt", rule.Chars)
+		case RuleAppendYear:
+			return fmt.Sprintf("Jahr %s angehängt", rule.Chars)
+		case RulePrependYear:
+			return fmt.Sprintf("Jahr %s vorangestellt", rule.Chars)
+		}
+		return fmt.Sprintf("'%c' durch '%c' ersetzt", chars[0], chars[1])
+	}
+
+	switch rule.Kind {
+	case RuleCapitalise:
+		return "capitalised"
+	case RuleUppercase:
+		return "uppercased"
+	case RuleToggleCase:
+		return "case toggled"
+	case RuleReverse:
+		return "reversed"
+	case RuleDuplicate:
+		return "duplicated"
+	case RuleAppend:
+		return fmt.Sprintf("'%s' appended", rule.Chars)
+	case RulePrepend:
+		return fmt.Sprintf("'%s' prepended", rule.Chars)
+	case RuleAppendYear:
+		return fmt.Sprintf("year %s appended", rule.Chars)
+	case RulePrependYear:
+		return fmt.Sprintf("year %s prepended", rule.Chars)
+	}
+	return fmt.Sprintf("'%c' replaced by '%c'", chars[0], chars[1])
+}
+
+// GetHintMangling provides a hint naming the entry of PasswordList the password is derived from and the rules used.
+// No hint is provided if the password is not derived or is the entry itself.
+func GetHintMangling(derivation Derivation, language string) string {
+	if !derivation.Found() || len(derivation.Rules) == 0 {
+		return ""
+	}
+
+	descriptions := make([]string, len(derivation.Rules))
+	for i, rule := range derivation.Rules {
+		descriptions[i] = describeRule(rule, language)
+	}
+
+	if language == "de" {
+		return fmt.Sprintf("Es lässt sich mit einfachen Regeln aus '%s' in unserer Passwortliste ableiten (%s), das probieren Angreifer als Erstes.",
+			derivation.Word, strings.Join(descriptions, ", "))
+	}
+	return fmt.Sprintf("It can be derived from '%s' in our password list by simple rules (%s), which attackers try first.",
+		derivation.Word, strings.Join(descriptions, ", "))
+}
@@ -0,0 +1,413 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Patterns of a Match found by CalculatePatterns.
+const (
+	// PatternDate is a date in German (DMY), US (MDY) or ISO (YMD) order, with or without separators.
+	PatternDate = "date"
+	// PatternYear is a four digit year.
+	PatternYear = "year"
+	// PatternSeason is the (German or English) name of a season.
+	PatternSeason = "season"
+	// PatternMonth is the (German or English) name of a month or its abbreviation.
+	PatternMonth = "month"
+	// PatternPhone is a group of digits looking like a phone number.
+	PatternPhone = "phone"
+	// PatternPIN is a group of four or six digits looking like a PIN.
+	PatternPIN = "pin"
+	// PatternDigitRepeat is a repeated block of digits, e.g. "1212".
+	PatternDigitRepeat = "digit-repeat"
+)
+
+// Now returns the current time. It is used to determine how close a year is to today.
+var Now = time.Now
+
+// minYear and maxYearAhead limit the years detected: from minYear up to maxYearAhead years after the current year.
+const minYear, maxYearAhead = 1900, 10
+
+// recentYears is the maximal distance (in years) to the current year for a year to be considered recent.
+const recentYears = 10
+
+// seasonNames are German and English names of seasons (lowercase, with and without umlauts).
+var seasonNames = []string{
+	"frühling", "fruehling", "fruhling", "frühjahr", "fruehjahr", "sommer", "herbst", "winter",
+	"spring", "summer", "autumn", "fall"}
+
+// monthNames are German and English names of months and their abbreviations (lowercase, with and without umlauts).
+var monthNames = []string{
+	"januar", "februar", "märz", "maerz", "marz", "april", "mai", "juni", "juli", "august",
+	"september", "oktober", "november", "dezember",
+	"january", "february", "march", "may", "june", "july", "october", "december",
+	"jan", "feb", "mär", "mar", "apr", "jun", "jul", "aug", "sep", "sept", "okt", "oct", "nov", "dez", "dec"}
+
+// yearEntropy returns the entropy (in bits) of a year: the closer it is to the current year, the easier it is to guess.
+func yearEntropy(year int) float64 {
+	distance := math.Abs(float64(year - Now().Year()))
+	return math.Log2(distance+1) + 1
+}
+
+// isRecentYear returns true if the given year is at most recentYears away from the current year.
+func isRecentYear(year int) bool {
+	return math.Abs(float64(year-Now().Year())) <= recentYears
+}
+
+// isValidYear returns true if the given four digit year is detected as year.
+func isValidYear(year int) bool {
+	return minYear <= year && year <= Now().Year()+maxYearAhead
+}
+
+// expandYear expands a two digit year to the closest four digit year not too far in the future.
+func expandYear(year int) int {
+	century := Now().Year() / 100 * 100
+	if century+year > Now().Year()+maxYearAhead {
+		return century - 100 + year
+	}
+	return century + year
+}
+
+// isValidDate returns true if day, month and year form a valid date.
+func isValidDate(day, month, year int) bool {
+	if month < 1 || month > 12 || day < 1 {
+		return false
+	}
+	return day <= time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// digitRuns returns the start and (exclusive) end indices of all maximal runs of digits in the password.
+func digitRuns(password []rune) [][2]int {
+	var runs [][2]int
+	for i := 0; i < len(password); {
+		if !unicode.IsDigit(password[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(password) && unicode.IsDigit(password[i]) {
+			i++
+		}
+		runs = append(runs, [2]int{start, i})
+	}
+	return runs
+}
+
+// atoi converts the given digits to an int.
+func atoi(digits []rune) int {
+	number, _ := strconv.Atoi(string(digits))
+	return number
+}
+
+// dateEntropy returns the entropy (in bits) of a date with given year, with or without separators.
+func dateEntropy(year int, separated bool) float64 {
+	entropy := math.Log2(366) + yearEntropy(year) + math.Log2(3) // day of year, year and order
+	if separated {
+		entropy += 2
+	}
+	return entropy
+}
+
+// dateOrders are the orders of day (d), month (m) and year (y) detected in dates (German, US and ISO).
+var dateOrders = []string{"dmy", "mdy", "ymd"}
+
+// parseDate interprets the given groups of digits as date in given order.
+// It returns the year and true if the groups form a valid date.
+func parseDate(groups [3][]rune, order string) (int, bool) {
+	var day, month, year int
+	for i, part := range order {
+		length := len(groups[i])
+		switch part {
+		case 'd':
+			day = atoi(groups[i])
+			if length > 2 {
+				return 0, false
+			}
+		case 'm':
+			month = atoi(groups[i])
+			if length > 2 {
+				return 0, false
+			}
+		case 'y':
+			year = atoi(groups[i])
+			switch length {
+			case 2:
+				year = expandYear(year)
+			case 4:
+				if !isValidYear(year) {
+					return 0, false
+				}
+			default:
+				return 0, false
+			}
+		}
+	}
+	return year, isValidDate(day, month, year)
+}
+
+// dateMatches returns all dates contained in the password as matches.
+// Dates with separators ("01.04.1990", "4/1/90", "1990-04-01") consist of three groups of digits separated by the same char,
+// dates without separators ("01041990", "900401") consist of two digits for day and month and two or four digits for the year.
+func dateMatches(password []rune) []Match {
+	var matches []Match
+	addDate := func(start, end int, groups [3][]rune, separated bool) {
+		for _, order := range dateOrders {
+			if year, ok := parseDate(groups, order); ok {
+				matches = append(matches, Match{
+					Pattern: PatternDate,
+					Start:   start,
+					End:     end,
+					Token:   string(password[start:end]),
+					Word:    order,
+					Entropy: dateEntropy(year, separated),
+				})
+				return
+			}
+		}
+	}
+
+	runs := digitRuns(password)
+
+	// dates with separators: three consecutive runs of digits separated by the same separator
+	for i := 0; i+2 < len(runs); i++ {
+		first, second, third := runs[i], runs[i+1], runs[i+2]
+		if second[0]-first[1] != 1 || third[0]-second[1] != 1 {
+			continue
+		}
+		separator := password[first[1]]
+		if separator != password[second[1]] || !strings.ContainsRune("./-", separator) {
+			continue
+		}
+
+		groups := [3][]rune{password[first[0]:first[1]], password[second[0]:second[1]], password[third[0]:third[1]]}
+		addDate(first[0], third[1], groups, true)
+	}
+
+	// dates without separators: every substring of six or eight digits of a run
+	for _, run := range runs {
+		for start := run[0]; start < run[1]; start++ {
+			for _, length := range []int{6, 8} {
+				end := start + length
+				if end > run[1] {
+					continue
+				}
+
+				digits := password[start:end]
+				for _, order := range dateOrders {
+					// the year takes the remaining digits beside two digits each for day and month
+					yearLength := length - 4
+					var groups [3][]rune
+					offset := 0
+					for i, part := range order {
+						partLength := 2
+						if part == 'y' {
+							partLength = yearLength
+						}
+						groups[i] = digits[offset : offset+partLength]
+						offset += partLength
+					}
+					if year, ok := parseDate(groups, order); ok {
+						matches = append(matches, Match{
+							Pattern: PatternDate,
+							Start:   start,
+							End:     end,
+							Token:   string(digits),
+							Word:    order,
+							Entropy: dateEntropy(year, false),
+						})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// yearMatches returns all four digit years contained in the password as matches.
+func yearMatches(password []rune) []Match {
+	var matches []Match
+	for _, run := range digitRuns(password) {
+		for start := run[0]; start+4 <= run[1]; start++ {
+			year := atoi(password[start : start+4])
+			if !isValidYear(year) {
+				continue
+			}
+
+			matches = append(matches, Match{
+				Pattern: PatternYear,
+				Start:   start,
+				End:     start + 4,
+				Token:   string(password[start : start+4]),
+				Word:    strconv.Itoa(year),
+				Entropy: yearEntropy(year),
+			})
+		}
+	}
+	return matches
+}
+
+// nameMatches returns all occurrences (case insensitive) of the given names in the password as matches with given pattern.
+func nameMatches(password []rune, names []string, pattern string) []Match {
+	lower := []rune(strings.ToLower(string(password)))
+
+	var matches []Match
+	for _, name := range names {
+		runes := []rune(name)
+		for start := 0; start+len(runes) <= len(lower); start++ {
+			if string(lower[start:start+len(runes)]) != name {
+				continue
+			}
+
+			token := password[start : start+len(runes)]
+			matches = append(matches, Match{
+				Pattern: pattern,
+				Start:   start,
+				End:     start + len(runes),
+				Token:   string(token),
+				Word:    name,
+				Entropy: math.Log2(float64(len(names))) + caseEntropy(token),
+			})
+		}
+	}
+	return matches
+}
+
+// digitMatches returns phone numbers, PINs and repeated blocks of digits contained in the password as matches.
+func digitMatches(password []rune) []Match {
+	var matches []Match
+	passwordRanks := getPasswordRanks()
+
+	for _, run := range digitRuns(password) {
+		start, end := run[0], run[1]
+		length := end - start
+
+		// phone numbers: 7 to 15 digits with a leading zero or plus sign (area and country codes are easy to guess)
+		international := start > 0 && password[start-1] == '+'
+		if 7 <= length && length <= 15 && (password[start] == '0' || international) {
+			phoneStart := start
+			if international {
+				phoneStart--
+			}
+			matches = append(matches, Match{
+				Pattern: PatternPhone,
+				Start:   phoneStart,
+				End:     end,
+				Token:   string(password[phoneStart:end]),
+				Entropy: float64(length-3) * math.Log2(10),
+			})
+		}
+
+		// PINs: groups of four or six digits, common ones are ranked by PasswordList
+		if length == 4 || length == 6 {
+			entropy := float64(length)*math.Log2(10) - 1
+			if rank, ok := passwordRanks[string(password[start:end])]; ok && math.Log2(float64(rank)) < entropy {
+				entropy = math.Log2(float64(rank))
+			}
+			matches = append(matches, Match{
+				Pattern: PatternPIN,
+				Start:   start,
+				End:     end,
+				Token:   string(password[start:end]),
+				Entropy: entropy,
+			})
+		}
+
+		// repeated blocks of digits: a block repeated at least twice
+		for blockStart := start; blockStart < end; blockStart++ {
+			for blockLength := 1; blockStart+2*blockLength <= end; blockLength++ {
+				block := string(password[blockStart : blockStart+blockLength])
+				repeatEnd := blockStart + blockLength
+				for repeatEnd+blockLength <= end && string(password[repeatEnd:repeatEnd+blockLength]) == block {
+					repeatEnd += blockLength
+				}
+
+				repeats := (repeatEnd - blockStart) / blockLength
+				if repeats < 2 {
+					continue
+				}
+				matches = append(matches, Match{
+					Pattern: PatternDigitRepeat,
+					Start:   blockStart,
+					End:     repeatEnd,
+					Token:   string(password[blockStart:repeatEnd]),
+					Word:    block,
+					Entropy: float64(blockLength)*math.Log2(10) + math.Log2(float64(repeats)),
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// CalculatePatterns returns all dates, years, season and month names, phone numbers, PINs and repeated blocks of digits
+// contained in the given password. Each match reports its span in the password.
+func CalculatePatterns(password string) []Match {
+	runes := []rune(password)
+
+	var matches []Match
+	matches = append(matches, dateMatches(runes)...)
+	matches = append(matches, yearMatches(runes)...)
+	matches = append(matches, nameMatches(runes, seasonNames, PatternSeason)...)
+	matches = append(matches, nameMatches(runes, monthNames, PatternMonth)...)
+	matches = append(matches, digitMatches(runes)...)
+	return matches
+}
+
+// patternDescriptions are the descriptions (en, de) of the patterns used in hints.
+var patternDescriptions = map[string][2]string{
+	PatternDate:        {"a date", "ein Datum"},
+	PatternYear:        {"a year", "eine Jahreszahl"},
+	PatternSeason:      {"a season", "eine Jahreszeit"},
+	PatternMonth:       {"a month", "einen Monat"},
+	PatternPhone:       {"a phone number", "eine Telefonnummer"},
+	PatternPIN:         {"a PIN", "eine PIN"},
+	PatternDigitRepeat: {"repeated digits", "wiederholte Ziffern"},
+}
+
+// GetHintPatterns provides a hint naming the dates, years, season and month names and digit patterns used by the segmentation.
+func GetHintPatterns(segmentation Segmentation, language string) string {
+	var parts []string
+	for _, segment := range segmentation.Segments {
+		description, ok := patternDescriptions[segment.Pattern]
+		if !ok {
+			continue
+		}
+
+		text := description[0]
+		if language == "de" {
+			text = description[1]
+		}
+		if segment.Pattern == PatternYear && isRecentYear(atoi([]rune(segment.Token))) {
+			text = "a recent year"
+			if language == "de" {
+				text = "eine aktuelle Jahreszahl"
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s ('%s')", text, segment.Token))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	list := parts[0]
+	if len(parts) > 1 {
+		conjunction := " and "
+		if language == "de" {
+			conjunction = " und "
+		}
+		list = strings.Join(parts[:len(parts)-1], ", ") + conjunction + parts[len(parts)-1]
+	}
+
+	if language == "de" {
+		return fmt.Sprintf("Es enthält %s, das ist leicht zu erraten.", list)
+	}
+	return fmt.Sprintf("It contains %s, which is easy to guess.", list)
+}
@@ -0,0 +1,742 @@
+package metric
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// minPhoneticLength is the minimal number of letters of a word matched phonetically
+// and minPhoneticCodeLength the minimal length of its phonetic code.
+const minPhoneticLength, minPhoneticCodeLength = 4, 2
+
+// phoneticPenalty is subtracted from the similarity (between 0 and 1) of a phonetic match,
+// minPhoneticSimilarity is the minimal (edit distance based) similarity of the letters of a phonetic match.
+const phoneticPenalty, minPhoneticSimilarity = 0.1, 0.7
+
+// colognePhoneticCode returns the code of the Kölner Phonetik of the letter at index i of the uppercase word (without 'H').
+func colognePhoneticCode(word []rune, i int) string {
+	at := func(j int) rune {
+		if j < 0 || j >= len(word) {
+			return 0
+		}
+		return word[j]
+	}
+	in := func(r rune, chars string) bool {
+		return r != 0 && strings.ContainsRune(chars, r)
+	}
+
+	char, previous, next := word[i], at(i-1), at(i+1)
+	switch {
+	case in(char, "AEIJOUYÄÖÜ"):
+		return "0"
+	case char == 'B', char == 'P' && next != 'H':
+		return "1"
+	case in(char, "DT"):
+		if in(next, "CSZ") {
+			return "8"
+		}
+		return "2"
+	case in(char, "FVW"), char == 'P':
+		return "3"
+	case in(char, "GKQ"):
+		return "4"
+	case char == 'C':
+		if i == 0 {
+			if in(next, "AHKLOQRUX") {
+				return "4"
+			}
+			return "8"
+		}
+		if in(next, "AHKOQUX") && !in(previous, "SZ") {
+			return "4"
+		}
+		return "8"
+	case char == 'X':
+		if in(previous, "CKQ") {
+			return "8"
+		}
+		return "48"
+	case char == 'L':
+		return "5"
+	case in(char, "MN"):
+		return "6"
+	case char == 'R':
+		return "7"
+	case in(char, "SZß"):
+		return "8"
+	}
+	return ""
+}
+
+// ColognePhonetic returns the code of the given word in the Kölner Phonetik, a phonetic algorithm for German words.
+// Chars other than letters are ignored.
+func ColognePhonetic(word string) string {
+	var letters []rune
+	for _, r := range strings.ToUpper(word) {
+		if unicode.IsLetter(r) {
+			letters = append(letters, r)
+		}
+	}
+
+	var raw strings.Builder
+	for i, char := range letters {
+		if char != 'H' {
+			raw.WriteString(colognePhoneticCode(letters, i))
+		}
+	}
+
+	// collapse repeated digits, then remove all zeros except at the start
+	var code []rune
+	previous := rune(0)
+	for _, digit := range raw.String() {
+		if digit != previous && (digit != '0' || len(code) == 0) {
+			code = append(code, digit)
+		}
+		previous = digit
+	}
+	return string(code)
+}
+
+// metaphoneMaxLength is the length of the codes of DoubleMetaphone.
+const metaphoneMaxLength = 4
+
+// doubleMetaphone holds the state of the Double Metaphone encoding of an uppercase word.
+type doubleMetaphone struct {
+	word      []rune
+	primary   strings.Builder
+	alternate strings.Builder
+}
+
+// at returns the char at index i, or 0 if i is out of range.
+func (m *doubleMetaphone) at(i int) rune {
+	if i < 0 || i >= len(m.word) {
+		return 0
+	}
+	return m.word[i]
+}
+
+// contains returns whether the word contains one of the given strings at index start.
+func (m *doubleMetaphone) contains(start int, candidates ...string) bool {
+	for _, candidate := range candidates {
+		length := len([]rune(candidate))
+		if start >= 0 && start+length <= len(m.word) && string(m.word[start:start+length]) == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// isVowel returns whether the char at index i is a vowel.
+func (m *doubleMetaphone) isVowel(i int) bool {
+	return strings.ContainsRune("AEIOUY", m.at(i)) && m.at(i) != 0
+}
+
+// slavoGermanic returns whether the word looks Slavic or Germanic.
+func (m *doubleMetaphone) slavoGermanic() bool {
+	word := string(m.word)
+	return strings.Contains(word, "W") || strings.Contains(word, "K") || strings.Contains(word, "CZ") || strings.Contains(word, "WITZ")
+}
+
+// add appends primary to the primary and alternate to the alternate code.
+func (m *doubleMetaphone) add(primary, alternate string) {
+	m.primary.WriteString(primary)
+	m.alternate.WriteString(alternate)
+}
+
+// addBoth appends code to both codes.
+func (m *doubleMetaphone) addBoth(code string) {
+	m.add(code, code)
+}
+
+// step returns 2 if the char after index i is the given one and 1 otherwise.
+func (m *doubleMetaphone) step(i int, next rune) int {
+	if m.at(i+1) == next {
+		return 2
+	}
+	return 1
+}
+
+// DoubleMetaphone returns the primary and alternate code of the given word in Double Metaphone,
+// a phonetic algorithm for English words (and names of other origins). Chars other than letters are ignored.
+func DoubleMetaphone(word string) (string, string) {
+	m := &doubleMetaphone{}
+	for _, r := range strings.ToUpper(word) {
+		if unicode.IsLetter(r) {
+			m.word = append(m.word, r)
+		}
+	}
+	if len(m.word) == 0 {
+		return "", ""
+	}
+
+	i := 0
+	if m.contains(0, "GN", "KN", "PN", "WR", "PS") {
+		i = 1
+	}
+	if m.at(0) == 'X' {
+		m.addBoth("S")
+		i = 1
+	}
+
+	for i < len(m.word) && (m.primary.Len() < metaphoneMaxLength || m.alternate.Len() < metaphoneMaxLength) {
+		switch char := m.word[i]; char {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if i == 0 {
+				m.addBoth("A")
+			}
+			i++
+		case 'B':
+			m.addBoth("P")
+			i += m.step(i, 'B')
+		case 'Ç':
+			m.addBoth("S")
+			i++
+		case 'C':
+			i = m.handleC(i)
+		case 'D':
+			i = m.handleD(i)
+		case 'F':
+			m.addBoth("F")
+			i += m.step(i, 'F')
+		case 'G':
+			i = m.handleG(i)
+		case 'H':
+			if (i == 0 || m.isVowel(i-1)) && m.isVowel(i+1) {
+				m.addBoth("H")
+				i += 2
+			} else {
+				i++
+			}
+		case 'J':
+			i = m.handleJ(i)
+		case 'K':
+			m.addBoth("K")
+			i += m.step(i, 'K')
+		case 'L':
+			i = m.handleL(i)
+		case 'M':
+			m.addBoth("M")
+			if m.at(i+1) == 'M' || m.contains(i-1, "UMB") && (i+1 == len(m.word)-1 || m.contains(i+2, "ER")) {
+				i += 2
+			} else {
+				i++
+			}
+		case 'N':
+			m.addBoth("N")
+			i += m.step(i, 'N')
+		case 'Ñ':
+			m.addBoth("N")
+			i++
+		case 'P':
+			if m.at(i+1) == 'H' {
+				m.addBoth("F")
+				i += 2
+			} else {
+				m.addBoth("P")
+				if m.contains(i+1, "P", "B") {
+					i += 2
+				} else {
+					i++
+				}
+			}
+		case 'Q':
+			m.addBoth("K")
+			i += m.step(i, 'Q')
+		case 'R':
+			if i == len(m.word)-1 && !m.slavoGermanic() && m.contains(i-2, "IE") && !m.contains(i-4, "ME", "MA") {
+				m.add("", "R")
+			} else {
+				m.addBoth("R")
+			}
+			i += m.step(i, 'R')
+		case 'S':
+			i = m.handleS(i)
+		case 'T':
+			i = m.handleT(i)
+		case 'V':
+			m.addBoth("F")
+			i += m.step(i, 'V')
+		case 'W':
+			i = m.handleW(i)
+		case 'X':
+			if !(i == len(m.word)-1 && (m.contains(i-3, "IAU", "EAU") || m.contains(i-2, "AU", "OU"))) {
+				m.addBoth("KS")
+			}
+			if m.contains(i+1, "C", "X") {
+				i += 2
+			} else {
+				i++
+			}
+		case 'Z':
+			i = m.handleZ(i)
+		default:
+			i++
+		}
+	}
+
+	primary, alternate := m.primary.String(), m.alternate.String()
+	if len(primary) > metaphoneMaxLength {
+		primary = primary[:metaphoneMaxLength]
+	}
+	if len(alternate) > metaphoneMaxLength {
+		alternate = alternate[:metaphoneMaxLength]
+	}
+	return primary, alternate
+}
+
+// handleC encodes the 'C' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleC(i int) int {
+	switch {
+	case m.germanicCH(i):
+		m.addBoth("K")
+		return i + 2
+	case i == 0 && m.contains(i, "CAESAR"):
+		m.addBoth("S")
+		return i + 2
+	case m.contains(i, "CH"):
+		return m.handleCH(i)
+	case m.contains(i, "CZ") && !m.contains(i-2, "WICZ"):
+		m.add("S", "X")
+		return i + 2
+	case m.contains(i+1, "CIA"):
+		m.addBoth("X")
+		return i + 3
+	case m.contains(i, "CC") && !(i == 1 && m.at(0) == 'M'):
+		if m.contains(i+2, "I", "E", "H") && !m.contains(i+2, "HU") {
+			if i == 1 && m.at(0) == 'A' || m.contains(i-1, "UCCEE", "UCCES") {
+				m.addBoth("KS")
+			} else {
+				m.addBoth("X")
+			}
+			return i + 3
+		}
+		m.addBoth("K")
+		return i + 2
+	case m.contains(i, "CK", "CG", "CQ"):
+		m.addBoth("K")
+		return i + 2
+	case m.contains(i, "CI", "CE", "CY"):
+		if m.contains(i, "CIO", "CIE", "CIA") {
+			m.add("S", "X")
+		} else {
+			m.addBoth("S")
+		}
+		return i + 2
+	}
+
+	m.addBoth("K")
+	switch {
+	case m.contains(i+1, " C", " Q", " G"):
+		return i + 3
+	case m.contains(i+1, "C", "K", "Q") && !m.contains(i+1, "CE", "CI"):
+		return i + 2
+	}
+	return i + 1
+}
+
+// germanicCH returns whether the 'C' at index i is part of a Germanic "ACH" like in "BACHER".
+func (m *doubleMetaphone) germanicCH(i int) bool {
+	if m.contains(i, "CHIA") {
+		return true
+	}
+	if i <= 1 || m.isVowel(i-2) || !m.contains(i-1, "ACH") {
+		return false
+	}
+	next := m.at(i + 2)
+	return next != 'I' && next != 'E' || m.contains(i-2, "BACHER", "MACHER")
+}
+
+// handleCH encodes the "CH" at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleCH(i int) int {
+	switch {
+	case i > 0 && m.contains(i, "CHAE"):
+		m.add("K", "X")
+	case i == 0 && (m.contains(i+1, "HARAC", "HARIS") || m.contains(i+1, "HOR", "HYM", "HIA", "HEM")) && !m.contains(0, "CHORE"):
+		m.addBoth("K")
+	case m.contains(0, "VAN ", "VON ", "SCH") || m.contains(i-2, "ORCHES", "ARCHIT", "ORCHID") || m.contains(i+2, "T", "S") ||
+		(m.contains(i-1, "A", "O", "U", "E") || i == 0) && (m.contains(i+2, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || i+1 == len(m.word)-1):
+		m.addBoth("K")
+	case i > 0:
+		if m.contains(0, "MC") {
+			m.addBoth("K")
+		} else {
+			m.add("X", "K")
+		}
+	default:
+		m.addBoth("X")
+	}
+	return i + 2
+}
+
+// handleD encodes the 'D' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleD(i int) int {
+	switch {
+	case m.contains(i, "DG"):
+		if m.contains(i+2, "I", "E", "Y") {
+			m.addBoth("J")
+			return i + 3
+		}
+		m.addBoth("TK")
+		return i + 2
+	case m.contains(i, "DT", "DD"):
+		m.addBoth("T")
+		return i + 2
+	}
+	m.addBoth("T")
+	return i + 1
+}
+
+// handleG encodes the 'G' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleG(i int) int {
+	switch next := m.at(i + 1); {
+	case next == 'H':
+		return m.handleGH(i)
+	case next == 'N':
+		switch {
+		case i == 1 && m.isVowel(0) && !m.slavoGermanic():
+			m.add("KN", "N")
+		case !m.contains(i+2, "EY") && m.at(i+1) != 'Y' && !m.slavoGermanic():
+			m.add("N", "KN")
+		default:
+			m.addBoth("KN")
+		}
+		return i + 2
+	case m.contains(i+1, "LI") && !m.slavoGermanic():
+		m.add("KL", "L")
+		return i + 2
+	case i == 0 && (next == 'Y' || m.contains(i+1, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		m.add("K", "J")
+		return i + 2
+	case (m.contains(i+1, "ER") || next == 'Y') && !m.contains(0, "DANGER", "RANGER", "MANGER") &&
+		!m.contains(i-1, "E", "I") && !m.contains(i-1, "RGY", "OGY"):
+		m.add("K", "J")
+		return i + 2
+	case m.contains(i+1, "E", "I", "Y") || m.contains(i-1, "AGGI", "OGGI"):
+		switch {
+		case m.contains(0, "VAN ", "VON ", "SCH") || m.contains(i+1, "ET"):
+			m.addBoth("K")
+		case m.contains(i+1, "IER"):
+			m.addBoth("J")
+		default:
+			m.add("J", "K")
+		}
+		return i + 2
+	case next == 'G':
+		m.addBoth("K")
+		return i + 2
+	}
+	m.addBoth("K")
+	return i + 1
+}
+
+// handleGH encodes the "GH" at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleGH(i int) int {
+	switch {
+	case i > 0 && !m.isVowel(i-1):
+		m.addBoth("K")
+	case i == 0:
+		if m.at(i+2) == 'I' {
+			m.addBoth("J")
+		} else {
+			m.addBoth("K")
+		}
+	case i > 1 && m.contains(i-2, "B", "H", "D") || i > 2 && m.contains(i-3, "B", "H", "D") || i > 3 && m.contains(i-4, "B", "H"):
+		// silent, e.g. "HUGH" or "BOUGHT"
+	case i > 2 && m.at(i-1) == 'U' && m.contains(i-3, "C", "G", "L", "R", "T"):
+		m.addBoth("F")
+	case i > 0 && m.at(i-1) != 'I':
+		m.addBoth("K")
+	}
+	return i + 2
+}
+
+// handleJ encodes the 'J' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleJ(i int) int {
+	if m.contains(i, "JOSE") || m.contains(0, "SAN ") {
+		if i == 0 && m.at(i+4) == ' ' || len(m.word) == 4 || m.contains(0, "SAN ") {
+			m.addBoth("H")
+		} else {
+			m.add("J", "H")
+		}
+		return i + 1
+	}
+
+	switch {
+	case i == 0:
+		m.add("J", "A")
+	case m.isVowel(i-1) && !m.slavoGermanic() && (m.at(i+1) == 'A' || m.at(i+1) == 'O'):
+		m.add("J", "H")
+	case i == len(m.word)-1:
+		m.add("J", "")
+	case !m.contains(i+1, "L", "T", "K", "S", "N", "M", "B", "Z") && !m.contains(i-1, "S", "K", "L"):
+		m.addBoth("J")
+	}
+	return i + m.step(i, 'J')
+}
+
+// handleL encodes the 'L' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleL(i int) int {
+	if m.at(i+1) != 'L' {
+		m.addBoth("L")
+		return i + 1
+	}
+
+	length := len(m.word)
+	if i == length-3 && m.contains(i-1, "ILLO", "ILLA", "ALLE") ||
+		(m.contains(length-2, "AS", "OS") || m.contains(length-1, "A", "O")) && m.contains(i-1, "ALLE") {
+		// Spanish, e.g. "CABRILLO"
+		m.add("L", "")
+	} else {
+		m.addBoth("L")
+	}
+	return i + 2
+}
+
+// handleS encodes the 'S' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleS(i int) int {
+	switch {
+	case m.contains(i-1, "ISL", "YSL"):
+		return i + 1
+	case i == 0 && m.contains(i, "SUGAR"):
+		m.add("X", "S")
+		return i + 1
+	case m.contains(i, "SH"):
+		if m.contains(i+1, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			m.addBoth("S")
+		} else {
+			m.addBoth("X")
+		}
+		return i + 2
+	case m.contains(i, "SIO", "SIA") || m.contains(i, "SIAN"):
+		if m.slavoGermanic() {
+			m.addBoth("S")
+		} else {
+			m.add("S", "X")
+		}
+		return i + 3
+	case i == 0 && m.contains(i+1, "M", "N", "L", "W") || m.contains(i+1, "Z"):
+		m.add("S", "X")
+		if m.contains(i+1, "Z") {
+			return i + 2
+		}
+		return i + 1
+	case m.contains(i, "SC"):
+		switch {
+		case m.at(i+2) == 'H':
+			switch {
+			case m.contains(i+3, "ER", "EN"):
+				m.add("X", "SK")
+			case m.contains(i+3, "OO", "UY", "ED", "EM"):
+				m.addBoth("SK")
+			case i == 0 && !m.isVowel(3) && m.at(3) != 'W':
+				m.add("X", "S")
+			default:
+				m.addBoth("X")
+			}
+		case m.contains(i+2, "I", "E", "Y"):
+			m.addBoth("S")
+		default:
+			m.addBoth("SK")
+		}
+		return i + 3
+	}
+
+	if i == len(m.word)-1 && m.contains(i-2, "AI", "OI") {
+		// French, e.g. "RESNAIS"
+		m.add("", "S")
+	} else {
+		m.addBoth("S")
+	}
+	if m.contains(i+1, "S", "Z") {
+		return i + 2
+	}
+	return i + 1
+}
+
+// handleT encodes the 'T' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleT(i int) int {
+	switch {
+	case m.contains(i, "TION"), m.contains(i, "TIA", "TCH"):
+		m.addBoth("X")
+		return i + 3
+	case m.contains(i, "TH") || m.contains(i, "TTH"):
+		if m.contains(i+2, "OM", "AM") || m.contains(0, "VAN ", "VON ", "SCH") {
+			m.addBoth("T")
+		} else {
+			m.add("0", "T")
+		}
+		return i + 2
+	}
+	m.addBoth("T")
+	if m.contains(i+1, "T", "D") {
+		return i + 2
+	}
+	return i + 1
+}
+
+// handleW encodes the 'W' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleW(i int) int {
+	switch {
+	case m.contains(i, "WR"):
+		m.addBoth("R")
+		return i + 2
+	case i == 0 && (m.isVowel(i+1) || m.contains(i, "WH")):
+		if m.isVowel(i + 1) {
+			m.add("A", "F")
+		} else {
+			m.addBoth("A")
+		}
+		return i + 1
+	case i == len(m.word)-1 && m.isVowel(i-1) || m.contains(i-1, "EWSKI", "EWSKY", "OWSKI", "OWSKY") || m.contains(0, "SCH"):
+		m.add("", "F")
+		return i + 1
+	case m.contains(i, "WICZ", "WITZ"):
+		m.add("TS", "FX")
+		return i + 4
+	}
+	return i + 1
+}
+
+// handleZ encodes the 'Z' at index i and returns the index of the next char to encode.
+func (m *doubleMetaphone) handleZ(i int) int {
+	if m.at(i+1) == 'H' {
+		m.addBoth("J")
+		return i + 2
+	}
+
+	if m.contains(i+1, "ZO", "ZI", "ZA") || m.slavoGermanic() && i > 0 && m.at(i-1) != 'T' {
+		m.add("S", "TS")
+	} else {
+		m.addBoth("S")
+	}
+	return i + m.step(i, 'Z')
+}
+
+// phoneticCodes returns the phonetic codes of the given word in the given language:
+// the Kölner Phonetik for German ("de") and the Double Metaphone codes otherwise.
+func phoneticCodes(word string, language string) []string {
+	if language == "de" {
+		return []string{ColognePhonetic(word)}
+	}
+	primary, alternate := DoubleMetaphone(word)
+	if alternate == primary {
+		return []string{primary}
+	}
+	return []string{primary, alternate}
+}
+
+// letters returns the lowercase letters of the given runes.
+func letters(runes []rune) []rune {
+	var result []rune
+	for _, r := range runes {
+		if unicode.IsLetter(r) {
+			result = append(result, unicode.ToLower(r))
+		}
+	}
+	return result
+}
+
+// phoneticMutex guards phoneticIndexes and phoneticList, as they are built lazily by concurrent requests.
+var phoneticMutex sync.Mutex
+
+// phoneticIndexes maps every language to the index of PasswordList by phonetic code:
+// every code is mapped to the (0-based) indices of the entries whose letters have that code.
+var phoneticIndexes map[string]map[string][]int
+
+// phoneticList is PasswordList when phoneticIndexes were built.
+var phoneticList [][]rune
+
+// getPhoneticIndex returns the index of PasswordList by phonetic code for the given language.
+// It is (re)built on first use and whenever PasswordList changed.
+func getPhoneticIndex(language string) map[string][]int {
+	phoneticMutex.Lock()
+	defer phoneticMutex.Unlock()
+
+	if phoneticIndexes == nil || !isPasswordList(phoneticList) {
+		phoneticIndexes, phoneticList = map[string]map[string][]int{}, PasswordList
+	}
+	if index, ok := phoneticIndexes[language]; ok {
+		return index
+	}
+
+	index := map[string][]int{}
+	for i, entry := range PasswordList {
+		word := letters(entry)
+		if len(word) < minPhoneticLength {
+			continue
+		}
+		for _, code := range phoneticCodes(string(word), language) {
+			if len([]rune(code)) >= minPhoneticCodeLength {
+				index[code] = append(index[code], i)
+			}
+		}
+	}
+	phoneticIndexes[language] = index
+	return index
+}
+
+// PhoneticMatch is an entry of PasswordList sounding like the letters of a password.
+// Score is the similarity in percent, Code the phonetic code both share.
+type PhoneticMatch struct {
+	Score               float64
+	MostSimilarPassword string
+	Code                string
+}
+
+// CalculatePhoneticSimilarity finds the entry of PasswordList whose letters sound like the letters of the given password in the
+// given language (see phoneticCodes) but are spelled differently. Of all entries sharing a phonetic code the one closest
+// in spelling is chosen.
+// The score is the (edit distance based) similarity of the letters to the entry weighted by the share of letters in the
+// password, reduced by phoneticPenalty. It is 0 if there is no such entry.
+func CalculatePhoneticSimilarity(password string, language string) PhoneticMatch {
+	runes := []rune(password)
+	word := letters(runes)
+	if len(word) < minPhoneticLength {
+		return PhoneticMatch{}
+	}
+
+	index := getPhoneticIndex(language)
+	calculator := newDistanceCalculator(word)
+
+	var match PhoneticMatch
+	bestSimilarity := minPhoneticSimilarity
+	for _, code := range phoneticCodes(string(word), language) {
+		for _, i := range index[code] {
+			entryLetters := letters(PasswordList[i])
+			if string(entryLetters) == string(word) {
+				// spelled alike, which is covered by CalculateSimilarity
+				continue
+			}
+			similarity := 1 - calculator.distance(entryLetters)/float64(len(word)+len(entryLetters))
+			if similarity > bestSimilarity {
+				bestSimilarity = similarity
+				match = PhoneticMatch{MostSimilarPassword: string(PasswordList[i]), Code: code}
+			}
+		}
+	}
+	if match.MostSimilarPassword == "" {
+		return PhoneticMatch{}
+	}
+
+	match.Score = (bestSimilarity*float64(len(word))/float64(len(runes)) - phoneticPenalty) * 100
+	if match.Score <= 0 {
+		return PhoneticMatch{}
+	}
+	return match
+}
+
+// GetHintPhonetic provides the entry of PasswordList sounding like the password as a hint.
+// No hint is provided if the score is not higher than 60 or not higher than the score of the (spelling) similarity
+// (both in percent) or if the entry is the one most similar in spelling, which the hint of the similarity names.
+func GetHintPhonetic(match PhoneticMatch, similarity Similarity, language string) string {
+	if match.Score <= 60 || match.Score <= similarity.Score || match.MostSimilarPassword == similarity.MostSimilarPassword {
+		return ""
+	}
+
+	if language == "de" {
+		return fmt.Sprintf("Es klingt wie '%s' aus unserer Passwortliste, das hilft Angreifern.", match.MostSimilarPassword)
+	}
+	return fmt.Sprintf("It sounds like '%s' in our password list, which helps attackers.", match.MostSimilarPassword)
+}
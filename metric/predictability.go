@@ -2,163 +2,468 @@ package metric
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"unicode"
 )
 
 // PasswordList is an array of []rune(s) to store the password list in the programs heap.
 var PasswordList [][]rune
 
-// empty is dummy variable (for shortage) for type empty struct.
-var empty struct{}
-
-// leetspeakMap is HashMap, containing HashMaps used to translates relevant lower-/uppercase letters to special ascii characters looking similar.
-// Using a normal letter as key, contained value is also a HashMap containing similar special chars and numbers, looking similar (leet version) to the letter.
-// The second HashMap is just used as a HashSet for quick occur checking.
-var leetspeakMap = map[rune]map[rune]struct{}{
-	'a': {'4': empty, '@': empty},
-	'b': {'8': empty},
-	'c': {'(': empty, '{': empty, '[': empty, '<': empty},
-	'e': {'3': empty},
-	'g': {'6': empty, '9': empty},
-	'i': {'1': empty, '!': empty, '|': empty},
-	'l': {'1': empty, '|': empty, '7': empty},
-	'o': {'0': empty},
-	's': {'$': empty, '5': empty},
-	't': {'+': empty, '7': empty},
-	'x': {'%': empty},
-	'z': {'2': empty},
-	'A': {'4': empty, '@': empty},
-	'B': {'8': empty},
-	'C': {'(': empty, '{': empty, '[': empty, '<': empty},
-	'E': {'3': empty},
-	'G': {'6': empty, '9': empty},
-	'I': {'1': empty, '!': empty, '|': empty},
-	'L': {'1': empty, '|': empty, '7': empty},
-	'O': {'0': empty},
-	'S': {'$': empty, '5': empty},
-	'T': {'+': empty, '7': empty},
-	'X': {'%': empty},
-	'Z': {'2': empty}}
-
-// leetCheck compares char1 and char2 on leet-similarity by looking up the presence of char2 in char1's HashMap (see leetspeakMap) and vice versa.
-// Only returns true if char1 / char2 occurs in the HashMap of char2 / char1.
-func leetCheck(char1, char2 rune) bool {
-	if val, ok := leetspeakMap[char1]; ok {
-		// for char1 exist leet chars
-		if _, ok := val[char2]; ok {
-			// char2 is a leet char of char1
-			return true
-		}
-	}
-	if val, ok := leetspeakMap[char2]; ok {
-		if _, ok := val[char1]; ok {
-			return true
-		}
-	}
-	return false
-}
-
-// min returns the minimum value of a, b and c.
-func min(a, b, c int) int {
-	if a < b {
-		if a < c {
-			return a
-		}
-	} else {
-		if b < c {
-			return b
-		}
-	}
-	return c
-}
-
-// calculateDistance calculates and returns the levenshtein distance between strings a and b.
-// aLength is required so that the the length of string a is not needed to be recalculated.
-// aColumn is required for an efficient memory usage of the algorithm by providing a pre-constructed int vector of size aLenght.
-// This implemention is optimized to use O(a) space.
+// PasswordListSource is a password list appended to PasswordList: Label names the list and Start is the index of its
+// first entry in PasswordList.
+type PasswordListSource struct {
+	Label string
+	Start int
+}
+
+// PasswordListSources are the lists PasswordList consists of, ordered by Start.
+// Entries before the first source (e.g. set directly by tests) belong to an unlabelled list.
+var PasswordListSources []PasswordListSource
+
+// passwordListEntry returns the label of the list containing the entry of PasswordList at index i
+// and the (1-based) rank of the entry in that list.
+func passwordListEntry(i int) (string, int) {
+	label, start := "", 0
+	for _, source := range PasswordListSources {
+		if source.Start > i {
+			break
+		}
+		label, start = source.Label, source.Start
+	}
+	return label, i - start + 1
+}
+
+// distanceCalculator calculates the distance of a fixed string a to other strings using DistanceCostModel.
+// tokens are the leet tokens of a (see leetTokensEndingAt) indexed by the last rune of the token they may be substituted
+// with, deletions the costs of deleting each char of a, substitutions the costs of substituting each char of a by an
+// ASCII char, columns the last columns of the distance matrix and lower the lowercase other string, pre-constructed
+// for an efficient memory usage.
+type distanceCalculator struct {
+	a             []rune
+	model         CostModel
+	tokens        []map[rune][]leetToken
+	deletions     []float64
+	substitutions [][asciiSize]float64
+	columns       [][]float64
+	lower         []rune
+}
+
+// asciiSize is the number of ASCII chars, whose substitution costs are cached by distanceCalculator.
+const asciiSize = 128
+
+// newDistanceCalculator returns a distanceCalculator for string a.
+func newDistanceCalculator(a []rune) *distanceCalculator {
+	model := DistanceCostModel
+
+	// keep the columns needed for leet tokens and transpositions
+	columns := make([][]float64, maxInt(leetTokenLength, 2)+1)
+	for i := range columns {
+		columns[i] = make([]float64, len(a)+1)
+	}
+
+	deletions := make([]float64, len(a))
+	substitutions := make([][asciiSize]float64, len(a))
+	for y, r := range a {
+		deletions[y] = model.Deletion(r)
+		for s := range substitutions[y] {
+			substitutions[y][s] = model.Substitution(r, rune(s))
+		}
+	}
+
+	// index the leet tokens ending at each index by the last rune of their counterpart, leaving indices without tokens nil
+	tokens := make([]map[rune][]leetToken, len(a)+1)
+	for y, tokensEndingAtY := range leetTokensEndingAt(a) {
+		for _, token := range tokensEndingAtY {
+			if tokens[y] == nil {
+				tokens[y] = map[rune][]leetToken{}
+			}
+			last := token.other[len(token.other)-1]
+			tokens[y][last] = append(tokens[y][last], token)
+		}
+	}
+
+	return &distanceCalculator{a: a, model: model, tokens: tokens, deletions: deletions, substitutions: substitutions, columns: columns}
+}
+
+// maxInt returns the maximum of a and b.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// minDistance returns a lower bound of the distance between a and a string of given length.
+func (c *distanceCalculator) minDistance(bLength int) float64 {
+	return float64(abs(len(c.a)-bLength)) * minFloat(leetLengthCost, c.model.MinInsertionDeletion())
+}
+
+// distance calculates and returns the weighted Damerau-Levenshtein distance (optimal string alignment) between strings a and b.
+// This implemention is optimized to use O(a) space by only keeping the last columns needed for leet tokens and transpositions.
 // Levenshtein distance between two words is the minimum number of single-character edits (insertions, deletions or substitutions)
 // required to change one word into the other (see http://en.wikipedia.org/wiki/Levenshtein_distance).
-func calculateDistance(a, b []rune, aLength int, aColumn []int) int {
-	var cost, lastDiagonalValue, oldDiagonalValue int
+// Additionally, two adjacent chars may be swapped and a token of leetTable may be substituted by its counterpart
+// (e.g. "|-|" by "h") at the cost of the substitution.
+func (c *distanceCalculator) distance(b []rune) float64 {
+	aLength, bLength := len(c.a), len(b)
+	column := func(x int) []float64 { return c.columns[x%len(c.columns)] }
 
-	// calculate length of b
-	bLength := len(b)
+	// leet tokens are compared case insensitively
+	c.lower = c.lower[:0]
+	for _, r := range b {
+		c.lower = append(c.lower, unicode.ToLower(r))
+	}
 
-	// initialize aColumn ascending (first columns values)
+	// initialize first column ascending (deleting every char of a)
+	column(0)[0] = 0
 	for y := 1; y <= aLength; y++ {
-		aColumn[y] = y
+		column(0)[y] = column(0)[y-1] + c.deletions[y-1]
 	}
 
 	for x := 1; x <= bLength; x++ { // outer loop moving column to the right
-		// updates to current column's first row entry (simply the number of the column)
-		aColumn[0] = x
+		current, previous := column(x), column(x-1)
+		char2, lowerChar2 := b[x-1], c.lower[x-1]
+		insertion := c.model.Insertion(char2)
 
-		lastDiagonalValue = x - 1
+		// updates to current column's first row entry (inserting every char of b)
+		current[0] = previous[0] + insertion
 
 		for y := 1; y <= aLength; y++ { // inner loop updating current column
-			oldDiagonalValue = aColumn[y]
-
 			// now comparing characters at same index in both strings
-			char1, char2 := a[y-1], b[x-1]
-
-			if char1 == char2 {
-				// same char -> change distance is 0
-				cost = 0
-			} else if unicode.ToLower(char1) == unicode.ToLower(char2) || leetCheck(char1, char2) {
-				// char just up/down shifted or similar to leet -> change distance is 1
-				cost = 1
+			char1 := c.a[y-1]
+
+			var cost float64
+			if char2 < asciiSize {
+				cost = c.substitutions[y-1][char2]
 			} else {
-				// char is completely different -> change distance is 2
-				cost = 2
+				cost = c.model.Substitution(char1, char2)
 			}
 
 			// current cells value gets assigned the minimum of:
-			//  previous horizontal left cell's value  +1
-			//  previous vertical above cell's value +1
-			//  previous diagonal up left cell's value + cost (0: similar, 1: upper/lower change OR leet translation, 2: else)
-			aColumn[y] = min(aColumn[y]+1, aColumn[y-1]+1, lastDiagonalValue+cost)
-			lastDiagonalValue = oldDiagonalValue
+			//  previous horizontal left cell's value + cost of inserting char2
+			//  previous vertical above cell's value + cost of deleting char1
+			//  previous diagonal up left cell's value + cost of substituting char1 by char2
+			value := minFloat(minFloat(previous[y]+insertion, current[y-1]+c.deletions[y-1]), previous[y-1]+cost)
+
+			// swap two adjacent chars of a
+			if x > 1 && y > 1 && char1 == b[x-2] && c.a[y-2] == char2 && char1 != char2 {
+				value = minFloat(value, column(x-2)[y-2]+c.model.Transposition(c.a[y-2], char1))
+			}
+
+			// substitute a leet token of a ending here by its counterpart ending here in b
+			for _, token := range c.tokens[y][lowerChar2] {
+				if start := x - len(token.other); hasTokenAt(c.lower, start, token.other) {
+					value = minFloat(value, column(start)[y-token.length]+token.cost)
+				}
+			}
+			current[y] = value
 		}
 	}
 
-	// return value of lower right corner of (virtual) matrix, containing the levenshtein distance
-	return aColumn[aLength]
+	// return value of lower right corner of (virtual) matrix, containing the distance
+	return column(bLength)[aLength]
 }
 
-//CalculatePredictability calculates the predictability of the basePassword with the given passwordList
-func CalculatePredictability(basePasswordString string) (float64, string) {
+// permutationBound is a lower bound of the distance between any permutation of a string a (e.g. a rotation or the
+// reversal) and other strings, based on the chars (case insensitive) only one of them contains: every edit changes at
+// most one of these chars of each string, a leet token at most as many as it is long. counts are the lowercase chars
+// of a, extraCost, missingCost and eitherCost the minimal costs per char only a, only the other or either string
+// contains. A bound of a containing non-ASCII chars is invalid and always 0.
+type permutationBound struct {
+	counts                             [asciiSize]int
+	extraCost, missingCost, eitherCost float64
+	valid                              bool
+}
+
+// newPermutationBound returns the permutationBound of string a.
+func newPermutationBound(a []rune) *permutationBound {
+	model := DistanceCostModel
+	bound := &permutationBound{}
+	for _, r := range a {
+		if r >= asciiSize {
+			return bound
+		}
+		bound.counts[unicode.ToLower(r)]++
+	}
+
+	// substitutions of chars only differing in case do not change the chars of a
+	substitution := math.Inf(1)
+	for _, r := range a {
+		for s := rune(0); s < asciiSize; s++ {
+			if unicode.ToLower(r) != unicode.ToLower(s) {
+				substitution = minFloat(substitution, model.Substitution(r, s))
+			}
+		}
+	}
+	indel := model.MinInsertionDeletion()
+	bound.extraCost, bound.missingCost, bound.eitherCost = minFloat(indel, substitution), minFloat(indel, substitution), minFloat(indel, substitution/2)
+
+	// only leet tokens contained in a can be substituted
+	for _, leetSubstitution := range leetTable {
+		plain, leet := []rune(strings.ToLower(leetSubstitution.Plain)), []rune(strings.ToLower(leetSubstitution.Leet))
+		for _, tokens := range [][2][]rune{{plain, leet}, {leet, plain}} {
+			own, other := tokens[0], tokens[1]
+			if bound.contains(own) {
+				cost := leetSubstitution.Cost
+				bound.extraCost = minFloat(bound.extraCost, cost/float64(len(own)))
+				bound.missingCost = minFloat(bound.missingCost, cost/float64(len(other)))
+				bound.eitherCost = minFloat(bound.eitherCost, cost/float64(len(own)+len(other)))
+			}
+		}
+	}
+
+	bound.valid = true
+	return bound
+}
+
+// contains returns whether the chars of a contain all chars of the given lowercase token.
+func (p *permutationBound) contains(token []rune) bool {
+	for _, r := range token {
+		if r >= asciiSize {
+			return false
+		}
+		count := 0
+		for _, other := range token {
+			if other == r {
+				count++
+			}
+		}
+		if count > p.counts[r] {
+			return false
+		}
+	}
+	return true
+}
+
+// distance returns a lower bound of the distance between any permutation of a and string b.
+func (p *permutationBound) distance(b []rune) float64 {
+	if !p.valid {
+		return 0
+	}
+	counts := p.counts
+	for _, r := range b {
+		if r >= asciiSize {
+			return 0
+		}
+		counts[unicode.ToLower(r)]--
+	}
 
-	// translate string to rune array
-	basePassword := []rune(basePasswordString)
-	// calculate length of basePassword
-	basePasswordLength := len(basePassword)
-	// create column for optimized levenshtein calculation
-	basePasswordColumn := make([]int, basePasswordLength+1)
+	extra, missing := 0, 0
+	for _, count := range counts {
+		if count > 0 {
+			extra += count
+		} else {
+			missing -= count
+		}
+	}
+	return math.Max(math.Max(float64(extra)*p.extraCost, float64(missing)*p.missingCost), float64(extra+missing)*p.eitherCost)
+}
+
+// Transforms of a password variant compared to the passwords of PasswordList.
+const (
+	// TransformNone is the password itself.
+	TransformNone = ""
+	// TransformReverse is the password written backwards, e.g. "drowssap".
+	TransformReverse = "reverse"
+	// TransformRotation is a cyclic rotation of the password, e.g. "wordpass".
+	TransformRotation = "rotation"
+	// TransformDuplicate is one half of a password consisting of the same string twice, e.g. "passpass".
+	TransformDuplicate = "duplicate"
+)
+
+// transformPenalty is subtracted from the similarity (between 0 and 1) of a transformed variant of the password.
+const transformPenalty = 0.05
+
+// maxRotationLength is the maximal length of a password whose rotations are compared,
+// which limits the number of compared variants to maxRotationLength+1.
+const maxRotationLength = 12
+
+// Similarity is the greatest similarity (in percent) of any variant of a password to the passwords of PasswordList.
+// Transform is the transform of the password resulting in Variant, which is most similar to MostSimilarPassword.
+type Similarity struct {
+	Score               float64
+	MostSimilarPassword string
+	Transform           string
+	Variant             string
+}
 
-	// initialize similarity with zero
-	greatestSimilarity := float64(0)
-	mostSimilarPassword := ""
+// passwordVariant is a transformed variant of a password.
+type passwordVariant struct {
+	runes     []rune
+	transform string
+}
 
-	// iterate over every password in passwordList to calc distance and the resulting similarity
-	for _, currentPassword := range PasswordList {
+// passwordVariants returns the password itself, its reversal, its rotations and the half of a duplicated password.
+func passwordVariants(password []rune) []passwordVariant {
+	variants := []passwordVariant{{password, TransformNone}}
+	seen := map[string]bool{string(password): true}
+	add := func(runes []rune, transform string) {
+		if !seen[string(runes)] {
+			seen[string(runes)] = true
+			variants = append(variants, passwordVariant{runes, transform})
+		}
+	}
 
-		distance := calculateDistance(basePassword, currentPassword, basePasswordLength, basePasswordColumn)
-		lengthSum := float64(basePasswordLength + len(currentPassword))
+	reversed := make([]rune, len(password))
+	for i, r := range password {
+		reversed[len(password)-1-i] = r
+	}
+	add(reversed, TransformReverse)
 
-		// see slide 23 of theory presentation
-		currentSimilarity := 1 - float64(distance)/lengthSum
+	if len(password) <= maxRotationLength {
+		for shift := 1; shift < len(password); shift++ {
+			add(append(append([]rune{}, password[shift:]...), password[:shift]...), TransformRotation)
+		}
+	}
 
-		// only cosider greatest similarity
-		if currentSimilarity > greatestSimilarity {
-			// update similarity
-			greatestSimilarity = currentSimilarity
-			mostSimilarPassword = string(currentPassword)
+	if half := len(password) / 2; len(password)%2 == 0 && half > 0 {
+		first, second := password[:half], password[half:]
+		if newDistanceCalculator(first).distance(second) <= float64(half/2) {
+			add(first, TransformDuplicate)
 		}
 	}
+	return variants
+}
+
+// SimilarPassword is an entry of PasswordList similar to a password: Label names the list containing it, Rank is its
+// (1-based) rank in that list, Distance the distance of Variant (the password transformed by Transform) to it
+// and Similarity the resulting similarity in percent.
+type SimilarPassword struct {
+	Password   string
+	Label      string
+	Rank       int
+	Distance   float64
+	Similarity float64
+	Transform  string
+	Variant    string
+	index      int
+}
+
+// moreSimilar returns whether a is ranked before b: by greater similarity and for equal similarities
+// by the position in PasswordList, so ties are broken deterministically in favour of more common passwords.
+func moreSimilar(a, b SimilarPassword) bool {
+	if a.Similarity != b.Similarity {
+		return a.Similarity > b.Similarity
+	}
+	return a.index < b.index
+}
+
+// CalculateSimilarPasswords returns the k entries of PasswordList most similar to the given password (or less if the
+// list is shorter), comparing the password itself, its reversal, its rotations and the half of a duplicated password.
+// The similarity of transformed variants is reduced by transformPenalty, every entry is reported with its most similar
+// variant only. Entries are ordered by moreSimilar.
+func CalculateSimilarPasswords(password string, k int) []SimilarPassword {
+	var top []SimilarPassword
+	if k <= 0 {
+		return top
+	}
+
+	// insert adds or improves the given entry, keeping top sorted and at most k entries long
+	insert := func(candidate SimilarPassword) {
+		for i, entry := range top {
+			if entry.index == candidate.index {
+				if !moreSimilar(candidate, entry) {
+					return
+				}
+				top = append(top[:i], top[i+1:]...)
+				break
+			}
+		}
+		position := sort.Search(len(top), func(i int) bool { return moreSimilar(candidate, top[i]) })
+		if position >= k {
+			return
+		}
+		top = append(top, SimilarPassword{})
+		copy(top[position+1:], top[position:])
+		top[position] = candidate
+		if len(top) > k {
+			top = top[:k]
+		}
+	}
+
+	variants := passwordVariants([]rune(password))
+	calculators := make([]*distanceCalculator, len(variants))
+	for v, variant := range variants {
+		calculators[v] = newDistanceCalculator(variant.runes)
+	}
+	// all variants but the half of a duplicated password are permutations of the password
+	bound := newPermutationBound([]rune(password))
+
+	// iterate over every password in passwordList to calc distance and the resulting similarity of every variant
+	for i, currentPassword := range PasswordList {
+		// the bound of the permutations is calculated at most once per password of passwordList
+		permutationDistance := -1.0
+
+		for v, variant := range variants {
+			lengthSum := float64(len(variant.runes) + len(currentPassword))
+
+			penalty := 0.0
+			if variant.transform != TransformNone {
+				penalty = transformPenalty
+			}
+
+			// skip passwords that can not be among the k most similar ones because of their length or chars
+			if len(top) == k {
+				minDistance := calculators[v].minDistance(len(currentPassword))
+				if variant.transform != TransformDuplicate {
+					if permutationDistance < 0 {
+						permutationDistance = bound.distance(currentPassword)
+					}
+					minDistance = math.Max(minDistance, permutationDistance)
+				}
+				if (1-minDistance/lengthSum-penalty)*100 < top[k-1].Similarity {
+					continue
+				}
+			}
+
+			distance := calculators[v].distance(currentPassword)
+
+			// see slide 23 of theory presentation
+			currentSimilarity := (1 - distance/lengthSum - penalty) * 100
+			if currentSimilarity <= 0 || len(top) == k && currentSimilarity < top[k-1].Similarity {
+				continue
+			}
+
+			label, rank := passwordListEntry(i)
+			insert(SimilarPassword{
+				Password:   string(currentPassword),
+				Label:      label,
+				Rank:       rank,
+				Distance:   distance,
+				Similarity: currentSimilarity,
+				Transform:  variant.transform,
+				Variant:    string(variant.runes),
+				index:      i})
+		}
+	}
+	return top
+}
+
+// CalculateSimilarity calculates the greatest similarity of the given password to the passwords of PasswordList
+// (see CalculateSimilarPasswords).
+func CalculateSimilarity(password string) Similarity {
+	return SimilarityOf(CalculateSimilarPasswords(password, 1))
+}
+
+// SimilarityOf returns the similarity given by the first (most similar) of the given entries of PasswordList
+// as returned by CalculateSimilarPasswords, so the k most similar entries and the similarity need only one scan.
+func SimilarityOf(similarPasswords []SimilarPassword) Similarity {
+	if len(similarPasswords) == 0 {
+		return Similarity{}
+	}
+	top := similarPasswords[0]
+	return Similarity{Score: top.Similarity, MostSimilarPassword: top.Password, Transform: top.Transform, Variant: top.Variant}
+}
+
+//CalculatePredictability calculates the predictability of the basePassword with the given passwordList
+func CalculatePredictability(basePasswordString string) (float64, string) {
+	similarity := CalculateSimilarity(basePasswordString)
 
 	//  P = max(Similarity to username, Similarity to common list)
 	// currently no username -> predictability = similarity to common list
-	return greatestSimilarity * 100, mostSimilarPassword
+	return similarity.Score, similarity.MostSimilarPassword
 }
 
 // GetHintPredictability provides the most similar password as a hint if its predictability is higher than 50
@@ -186,3 +491,29 @@ func GetHintPredictability(mostSimilarPassword string, score float64, language s
 	}
 	return "No similar password was found in our list. Good job!"
 }
+
+// GetHintSimilarityTransform provides the transform of the password making it similar to the most similar password as a hint.
+// No hint is provided if the password itself is the most similar variant or the similarity is not higher than 60 (see GetHintPredictability).
+func GetHintSimilarityTransform(similarity Similarity, language string) string {
+	if similarity.Transform == TransformNone || similarity.Score <= 60 {
+		return ""
+	}
+
+	if language == "de" {
+		switch similarity.Transform {
+		case TransformReverse:
+			return fmt.Sprintf("Es ist eine rückwärts geschriebene Version von '%s', das probieren Angreifer auch.", similarity.MostSimilarPassword)
+		case TransformRotation:
+			return fmt.Sprintf("Es ist eine rotierte Version von '%s' ('%s'), das probieren Angreifer auch.", similarity.MostSimilarPassword, similarity.Variant)
+		}
+		return fmt.Sprintf("Es wiederholt eine Version von '%s' zweimal, das probieren Angreifer auch.", similarity.MostSimilarPassword)
+	}
+
+	switch similarity.Transform {
+	case TransformReverse:
+		return fmt.Sprintf("It is a version of '%s' written backwards, which attackers try as well.", similarity.MostSimilarPassword)
+	case TransformRotation:
+		return fmt.Sprintf("It is a rotated version of '%s' ('%s'), which attackers try as well.", similarity.MostSimilarPassword, similarity.Variant)
+	}
+	return fmt.Sprintf("It repeats a version of '%s' twice, which attackers try as well.", similarity.MostSimilarPassword)
+}
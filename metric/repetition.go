@@ -0,0 +1,239 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Patterns of a Match found by CalculateRepetition.
+const (
+	// PatternRepeat is a repeated char or block of chars, e.g. "aaaa" or "abcabc".
+	PatternRepeat = "repeat"
+	// PatternSequence is an ascending or descending sequence of letters, digits or ASCII codes, e.g. "abcd" or "9753".
+	PatternSequence = "sequence"
+)
+
+// minCharRepeats is the minimal number of repeats of a single char, minBlockRepeats the one of a block of chars.
+const minCharRepeats, minBlockRepeats = 3, 2
+
+// sequenceSteps are the steps between the codes of consecutive chars detected as sequence
+// and minSequenceLength the minimal length of a sequence for each step.
+var sequenceSteps = []int{1, -1, 2, -2}
+var minSequenceLength = map[int]int{1: 3, -1: 3, 2: 4, -2: 4}
+
+// Repetition is the result of the repetition and sequence detection.
+// Matches are the non-overlapping repeats and sequences found in the password, Reduced the password with every
+// repeat reduced to its block and every sequence reduced to its first char and EffectiveLength the length
+// of the password as it counts for its strength.
+type Repetition struct {
+	Matches         []Match
+	Reduced         string
+	EffectiveLength float64
+}
+
+// EffectiveComplexity returns the complexity of the password as it counts for its strength,
+// which is the complexity of the reduced password.
+func (r Repetition) EffectiveComplexity() float64 {
+	return CalculateComplexity(r.Reduced)
+}
+
+// repetitionKept returns the number of chars of a repeat or sequence match that are kept in the reduced password.
+func repetitionKept(match Match) int {
+	if match.Pattern == PatternRepeat {
+		return len([]rune(match.Word))
+	}
+	return 1
+}
+
+// repetitionCount returns how often the block of a repeat is repeated, or the length of a sequence.
+func repetitionCount(match Match) int {
+	length := match.End - match.Start
+	if match.Pattern == PatternRepeat {
+		return length / repetitionKept(match)
+	}
+	return length
+}
+
+// repeatMatches returns all repeated chars and blocks of chars contained in the password as matches.
+// For every start index and block length only the longest repeat is returned.
+func repeatMatches(password []rune) []Match {
+	var matches []Match
+	for start := range password {
+		for blockLength := 1; start+2*blockLength <= len(password); blockLength++ {
+			block := string(password[start : start+blockLength])
+			end := start + blockLength
+			for end+blockLength <= len(password) && string(password[end:end+blockLength]) == block {
+				end += blockLength
+			}
+
+			repeats := (end - start) / blockLength
+			if blockLength == 1 && repeats < minCharRepeats || repeats < minBlockRepeats {
+				continue
+			}
+
+			blockEntropy := 0.0
+			for _, r := range block {
+				blockEntropy += charEntropy(r)
+			}
+			matches = append(matches, Match{
+				Pattern: PatternRepeat,
+				Start:   start,
+				End:     end,
+				Token:   string(password[start:end]),
+				Word:    block,
+				Entropy: blockEntropy + math.Log2(float64(repeats)),
+			})
+		}
+	}
+	return matches
+}
+
+// sequenceMatches returns all ascending and descending sequences of letters (case insensitive), digits
+// and ASCII codes contained in the password as matches. Sequences continuing before their start are not returned.
+func sequenceMatches(password []rune) []Match {
+	codes := make([]int, len(password))
+	for i, r := range password {
+		codes[i] = int(unicode.ToLower(r))
+	}
+
+	var matches []Match
+	for _, step := range sequenceSteps {
+		for start := range codes {
+			if start > 0 && codes[start]-codes[start-1] == step {
+				continue
+			}
+
+			end := start + 1
+			for end < len(codes) && codes[end]-codes[end-1] == step {
+				end++
+			}
+			if end-start < minSequenceLength[step] {
+				continue
+			}
+
+			matches = append(matches, Match{
+				Pattern: PatternSequence,
+				Start:   start,
+				End:     end,
+				Token:   string(password[start:end]),
+				Word:    string(password[start]),
+				// first char, step and length of the sequence
+				Entropy: charEntropy(password[start]) + math.Log2(float64(len(sequenceSteps))) + math.Log2(float64(end-start)),
+			})
+		}
+	}
+	return matches
+}
+
+// repetitionMatches returns all repeats and sequences contained in the password as matches.
+func repetitionMatches(password []rune) []Match {
+	return append(repeatMatches(password), sequenceMatches(password)...)
+}
+
+// CalculateRepetition detects repeated chars, repeated blocks and sequences in the given password and calculates
+// its effective length. Overlapping repeats and sequences are resolved by preferring the ones saving the most chars.
+func CalculateRepetition(password string) Repetition {
+	runes := []rune(password)
+
+	candidates := repetitionMatches(runes)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		savedI := candidates[i].End - candidates[i].Start - repetitionKept(candidates[i])
+		savedJ := candidates[j].End - candidates[j].Start - repetitionKept(candidates[j])
+		if savedI != savedJ {
+			return savedI > savedJ
+		}
+		return candidates[i].Start < candidates[j].Start
+	})
+
+	// choose non-overlapping matches
+	used := make([]bool, len(runes))
+	var matches []Match
+	for _, candidate := range candidates {
+		overlaps := false
+		for i := candidate.Start; i < candidate.End; i++ {
+			overlaps = overlaps || used[i]
+		}
+		if overlaps {
+			continue
+		}
+		for i := candidate.Start; i < candidate.End; i++ {
+			used[i] = true
+		}
+		matches = append(matches, candidate)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	// reduce the password and count its effective length
+	var reduced []rune
+	effectiveLength := 0.0
+	position := 0
+	for _, match := range matches {
+		reduced = append(reduced, runes[position:match.Start]...)
+		reduced = append(reduced, runes[match.Start:match.Start+repetitionKept(match)]...)
+		effectiveLength += math.Log2(float64(repetitionCount(match)))
+		position = match.End
+	}
+	reduced = append(reduced, runes[position:]...)
+	effectiveLength += float64(len(reduced))
+
+	if effectiveLength > float64(len(runes)) {
+		effectiveLength = float64(len(runes))
+	}
+
+	return Repetition{Matches: matches, Reduced: string(reduced), EffectiveLength: effectiveLength}
+}
+
+// describeRepetition returns a description (en or de) of the given repeat or sequence match.
+func describeRepetition(match Match, language string) string {
+	if language == "de" {
+		if match.Pattern == PatternRepeat {
+			return fmt.Sprintf("'%s' wiederholt '%s' %d-mal", match.Token, match.Word, repetitionCount(match))
+		}
+		return fmt.Sprintf("'%s' ist eine Folge", match.Token)
+	}
+	if match.Pattern == PatternRepeat {
+		return fmt.Sprintf("'%s' repeats '%s' %d times", match.Token, match.Word, repetitionCount(match))
+	}
+	return fmt.Sprintf("'%s' is a sequence", match.Token)
+}
+
+// GetHintRepetition provides a hint explaining how repeats and sequences reduce the effective length of the password.
+// It returns an empty string if there are no repeats or sequences (see GetHintEffectiveLength).
+func GetHintRepetition(repetition Repetition, language string) string {
+	if len(repetition.Matches) == 0 {
+		return ""
+	}
+
+	descriptions := make([]string, len(repetition.Matches))
+	for i, match := range repetition.Matches {
+		descriptions[i] = describeRepetition(match, language)
+	}
+
+	if language == "de" {
+		return fmt.Sprintf("Wiederholungen und Folgen zählen kaum (%s), daher zählt sie nur wie die Länge %.0f.",
+			strings.Join(descriptions, ", "), math.Floor(repetition.EffectiveLength))
+	}
+	return fmt.Sprintf("Repeats and sequences barely count (%s), so it only counts as length %.0f.",
+		strings.Join(descriptions, ", "), math.Floor(repetition.EffectiveLength))
+}
+
+// GetHintRepetitionComplexity provides a hint explaining that repeats and sequences do not add complexity.
+// It returns an empty string if there are no repeats or sequences.
+func GetHintRepetitionComplexity(repetition Repetition, language string) string {
+	if len(repetition.Matches) == 0 {
+		return ""
+	}
+
+	tokens := make([]string, len(repetition.Matches))
+	for i, match := range repetition.Matches {
+		tokens[i] = fmt.Sprintf("'%s'", match.Token)
+	}
+
+	if language == "de" {
+		return fmt.Sprintf("Wiederholungen und Folgen wie %s erhöhen die Komplexität nicht.", strings.Join(tokens, ", "))
+	}
+	return fmt.Sprintf("Repeats and sequences like %s do not add complexity.", strings.Join(tokens, ", "))
+}
@@ -0,0 +1,215 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Patterns of a Match.
+const (
+	// PatternDictionary is a (case and leet aware) word of PasswordList.
+	PatternDictionary = "dictionary"
+	// PatternKeyboard is a walk over neighbouring keys of a keyboard (see KeyboardWalk).
+	PatternKeyboard = "keyboard"
+	// PatternFiller is a single char not belonging to any other pattern.
+	PatternFiller = "filler"
+)
+
+// segmentOverhead is the entropy (in bits) added for each segment that is not filler.
+// It prefers decompositions into fewer segments.
+const segmentOverhead = 1.0
+
+// Match is a part of a password recognised by one of the pattern detectors.
+// Start and End are the rune indices of the part in the password (End is exclusive) and
+// Entropy is the number of bits an attacker needs to guess the part using the knowledge of its pattern.
+type Match struct {
+	Pattern string
+	Start   int
+	End     int
+	Token   string
+	Word    string
+	Entropy float64
+}
+
+// Segmentation is the lowest-cost decomposition of a password into matches.
+// Entropy is the sum of the entropies of all segments, BruteForceEntropy the entropy of the password without any pattern.
+// KeyboardWalks and Patterns are all keyboard walks and patterns found in the password, whether segments or not.
+type Segmentation struct {
+	Segments          []Match
+	Entropy           float64
+	BruteForceEntropy float64
+	KeyboardWalks     []KeyboardWalk
+	Patterns          []Match
+}
+
+// Words returns the dictionary words of the segmentation in order of their occurrence.
+func (s Segmentation) Words() []string {
+	var words []string
+	for _, segment := range s.Segments {
+		if segment.Pattern == PatternDictionary {
+			words = append(words, segment.Word)
+		}
+	}
+	return words
+}
+
+// Predictability returns the predictability of the segmentation in percent, which is the relative
+// amount of entropy an attacker saves by guessing the segments instead of brute forcing every char.
+func (s Segmentation) Predictability() float64 {
+	if s.BruteForceEntropy <= 0 || s.Entropy >= s.BruteForceEntropy {
+		return 0
+	}
+	return (1 - s.Entropy/s.BruteForceEntropy) * 100
+}
+
+// charEntropy returns the entropy (in bits) of a single char, based on the size of its character set.
+func charEntropy(r rune) float64 {
+	switch {
+	case unicode.IsLower(r), unicode.IsUpper(r):
+		return math.Log2(26)
+	case unicode.IsNumber(r):
+		return math.Log2(10)
+	default:
+		return math.Log2(33)
+	}
+}
+
+// caseEntropy returns the entropy (in bits) needed to guess the capitalisation of a word.
+// Lowercase words need no additional bits, capitalised and uppercase words one bit.
+func caseEntropy(token []rune) float64 {
+	upper, letters := 0, 0
+	for _, r := range token {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return 0
+	case upper == letters || upper == 1 && unicode.IsUpper(token[0]):
+		return 1
+	}
+
+	// any other capitalisation: number of ways to choose the uppercase letters
+	return math.Log2(binomial(letters, upper))
+}
+
+// binomial returns the binomial coefficient n choose k.
+func binomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+// dictionaryMatches returns all dictionary words contained in the password as matches.
+func dictionaryMatches(password []rune) []Match {
+	var matches []Match
+	leetTokens := leetTokensStartingAt(password)
+	for start := range password {
+		for _, word := range findDictionaryWords(password, leetTokens, start) {
+			token := password[start:word.end]
+			matches = append(matches, Match{
+				Pattern: PatternDictionary,
+				Start:   start,
+				End:     word.end,
+				Token:   string(token),
+				Word:    word.word,
+				Entropy: math.Log2(float64(word.rank)) + caseEntropy(token) + float64(word.leet),
+			})
+		}
+	}
+	return matches
+}
+
+// segment returns the lowest-cost decomposition of the password into the given matches and filler chars.
+func segment(password []rune, matches []Match) Segmentation {
+	n := len(password)
+
+	// byEnd groups the matches by their end index
+	byEnd := make([][]Match, n+1)
+	for _, match := range matches {
+		byEnd[match.End] = append(byEnd[match.End], match)
+	}
+
+	// best[i] is the minimal entropy of password[:i], last[i] the last segment of that decomposition
+	best := make([]float64, n+1)
+	last := make([]Match, n+1)
+	bruteForce := 0.0
+	for i := 1; i <= n; i++ {
+		filler := charEntropy(password[i-1])
+		bruteForce += filler
+
+		best[i] = best[i-1] + filler
+		last[i] = Match{Pattern: PatternFiller, Start: i - 1, End: i, Token: string(password[i-1]), Entropy: filler}
+
+		for _, match := range byEnd[i] {
+			if cost := best[match.Start] + match.Entropy + segmentOverhead; cost < best[i] {
+				best[i] = cost
+				last[i] = match
+			}
+		}
+	}
+
+	// collect segments from the end
+	var segments []Match
+	for i := n; i > 0; i = last[i].Start {
+		segments = append([]Match{last[i]}, segments...)
+	}
+
+	return Segmentation{Segments: segments, Entropy: best[n], BruteForceEntropy: bruteForce}
+}
+
+// CalculateSegmentation calculates the lowest-cost decomposition of the given password into dictionary words
+// (case and leet aware), keyboard walks, patterns (see CalculatePatterns), repeats, sequences and filler chars.
+func CalculateSegmentation(password string) Segmentation {
+	runes := []rune(password)
+	walks := CalculateKeyboardWalks(password)
+	patterns := CalculatePatterns(password)
+
+	var matches []Match
+	matches = append(matches, dictionaryMatches(runes)...)
+	matches = append(matches, keyboardMatches(walks)...)
+	matches = append(matches, patterns...)
+	matches = append(matches, repetitionMatches(runes)...)
+
+	segmentation := segment(runes, matches)
+	segmentation.KeyboardWalks, segmentation.Patterns = walks, patterns
+	return segmentation
+}
+
+// GetHintSegmentation provides the dictionary words the password consists of as a hint.
+// No hint is provided if there are no words or if the password is just the given most similar password.
+func GetHintSegmentation(segmentation Segmentation, mostSimilarPassword string, language string) string {
+	words := segmentation.Words()
+	if len(words) == 0 || segmentation.Predictability() <= 20 ||
+		len(words) == 1 && len(segmentation.Segments) == 1 && words[0] == strings.ToLower(mostSimilarPassword) {
+		return ""
+	}
+
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = fmt.Sprintf("'%s'", word)
+	}
+
+	if language == "de" {
+		if len(words) == 1 {
+			return fmt.Sprintf("Es enthält das häufige Wort %s.", quoted[0])
+		}
+		return fmt.Sprintf("Es enthält die häufigen Wörter %s und %s.", strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+	}
+	if len(words) == 1 {
+		return fmt.Sprintf("It contains the common word %s.", quoted[0])
+	}
+	return fmt.Sprintf("It contains the common words %s and %s.", strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
@@ -0,0 +1,237 @@
+package metric
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Classes of a char in the class mask of a password.
+const (
+	maskUpper   = 'U'
+	maskLower   = 'l'
+	maskDigit   = 'd'
+	maskSpecial = 's'
+)
+
+// maskNonLetter is the class of digits and special characters in the template of a class mask.
+const maskNonLetter = 'n'
+
+// minTemplateFrequency is the minimal relative frequency of a template in PasswordList to consider it common.
+const minTemplateFrequency = 0.001
+
+// Structure is the result of the structural analysis of a password.
+// Mask is its class mask (e.g. "Ulllllllds" for "Password1!"), Template the mask normalised in length (e.g. "Uln",
+// see maskTemplate) and Frequency the relative frequency of the template among the passwords of PasswordList.
+type Structure struct {
+	Mask         string
+	Template     string
+	CapitalFirst bool
+	AllCaps      bool
+	DigitSuffix  bool
+	SymbolSuffix bool
+	Frequency    float64
+}
+
+// Common returns whether the template of the structure is common in PasswordList.
+func (s Structure) Common() bool {
+	return s.Frequency >= minTemplateFrequency
+}
+
+// Predictable returns whether the password places its uppercase letters, digits or special characters
+// the way most people do (first letter, all caps or appended) and that template is common.
+func (s Structure) Predictable() bool {
+	return s.Common() && (s.CapitalFirst || s.AllCaps || s.DigitSuffix || s.SymbolSuffix)
+}
+
+// ClassMask returns the class mask of the given password, which replaces every uppercase letter by 'U',
+// lowercase letter by 'l', digit by 'd' and any other char by 's'.
+func ClassMask(password string) string {
+	var mask strings.Builder
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			mask.WriteRune(maskUpper)
+		case unicode.IsLower(r):
+			mask.WriteRune(maskLower)
+		case unicode.IsNumber(r):
+			mask.WriteRune(maskDigit)
+		default:
+			mask.WriteRune(maskSpecial)
+		}
+	}
+	return mask.String()
+}
+
+// isLetterClass returns whether given class of a class mask is a letter.
+func isLetterClass(class rune) bool {
+	return class == maskUpper || class == maskLower
+}
+
+// capsPlacement returns the placement of the uppercase letters of a class mask:
+// "none", "first" (only the first letter), "all" (all letters) or "mixed".
+func capsPlacement(mask []rune) string {
+	upper, letters, firstLetter := 0, 0, -1
+	for i, class := range mask {
+		if isLetterClass(class) {
+			if firstLetter < 0 {
+				firstLetter = i
+			}
+			letters++
+		}
+		if class == maskUpper {
+			upper++
+		}
+	}
+
+	switch {
+	case upper == 0:
+		return "none"
+	case upper == letters && letters > 1:
+		return "all"
+	case upper == 1 && mask[firstLetter] == maskUpper:
+		return "first"
+	}
+	return "mixed"
+}
+
+// nonLetterPlacement returns the placement of the digits and special characters of a class mask:
+// "none", "only" (no letters), "prefix" (before all letters), "suffix" (after all letters) or "middle".
+func nonLetterPlacement(mask []rune) string {
+	firstLetter, lastLetter := -1, -1
+	nonLetters := 0
+	for i, class := range mask {
+		if isLetterClass(class) {
+			if firstLetter < 0 {
+				firstLetter = i
+			}
+			lastLetter = i
+		} else {
+			nonLetters++
+		}
+	}
+
+	switch {
+	case nonLetters == 0:
+		return "none"
+	case firstLetter < 0:
+		return "only"
+	case nonLetters == len(mask)-1-lastLetter:
+		return "suffix"
+	case nonLetters == firstLetter:
+		return "prefix"
+	}
+	return "middle"
+}
+
+// maskTemplate returns the template of a class mask, which is the mask normalised in length by collapsing runs of
+// the same class, e.g. "Uln" for "Ulllllllds" and "Ulllld". Digits and special characters share the class 'n', as the
+// shipped password lists contain too few special characters to learn their placement apart from that of digits.
+func maskTemplate(mask []rune) string {
+	var template []rune
+	for _, class := range mask {
+		if !isLetterClass(class) {
+			class = maskNonLetter
+		}
+		if len(template) == 0 || template[len(template)-1] != class {
+			template = append(template, class)
+		}
+	}
+	return string(template)
+}
+
+// templateMutex guards templateFrequencies and templateList, as they are built lazily by concurrent requests.
+var templateMutex sync.Mutex
+
+// templateFrequencies maps every template to its relative frequency in PasswordList.
+var templateFrequencies map[string]float64
+
+// templateList is PasswordList when templateFrequencies was built.
+var templateList [][]rune
+
+// getTemplateFrequencies returns the relative frequencies of the templates of PasswordList.
+// They are (re)built on first use and whenever PasswordList changed.
+func getTemplateFrequencies() map[string]float64 {
+	templateMutex.Lock()
+	defer templateMutex.Unlock()
+
+	if templateFrequencies != nil && isPasswordList(templateList) {
+		return templateFrequencies
+	}
+
+	counts := map[string]int{}
+	for _, entry := range PasswordList {
+		counts[maskTemplate([]rune(ClassMask(string(entry))))]++
+	}
+
+	templateFrequencies = map[string]float64{}
+	for key, count := range counts {
+		templateFrequencies[key] = float64(count) / float64(len(PasswordList))
+	}
+	templateList = PasswordList
+	return templateFrequencies
+}
+
+// CalculateStructure analyses the structure of the given password: its class mask, the placement of its
+// uppercase letters, digits and special characters and the frequency of the template of its mask in PasswordList.
+func CalculateStructure(password string) Structure {
+	mask := []rune(ClassMask(password))
+	structure := Structure{Mask: string(mask), Template: maskTemplate(mask)}
+
+	switch capsPlacement(mask) {
+	case "first":
+		structure.CapitalFirst = true
+	case "all":
+		structure.AllCaps = true
+	}
+
+	if nonLetterPlacement(mask) == "suffix" {
+		structure.DigitSuffix = strings.ContainsRune(string(mask), maskDigit)
+		structure.SymbolSuffix = strings.ContainsRune(string(mask), maskSpecial)
+	}
+
+	structure.Frequency = getTemplateFrequencies()[structure.Template]
+	return structure
+}
+
+// GetHintStructure provides a hint naming the common template the password follows and how to avoid it.
+// It returns an empty string if the password does not follow a common template.
+func GetHintStructure(structure Structure, language string) string {
+	if !structure.Predictable() {
+		return ""
+	}
+
+	var hints []string
+	if language == "de" {
+		hints = append(hints, fmt.Sprintf("Es folgt einem häufigen Muster (%s).", structure.Mask))
+		if structure.CapitalFirst {
+			hints = append(hints, "Schreibe lieber einen Buchstaben in der Mitte groß als den ersten.")
+		}
+		if structure.AllCaps {
+			hints = append(hints, "Mische Groß- und Kleinbuchstaben, statt alles großzuschreiben.")
+		}
+		if structure.DigitSuffix {
+			hints = append(hints, "Verschiebe deine Ziffern in die Mitte.")
+		}
+		if structure.SymbolSuffix {
+			hints = append(hints, "Verschiebe deine Sonderzeichen in die Mitte.")
+		}
+		return strings.Join(hints, " ")
+	}
+
+	hints = append(hints, fmt.Sprintf("It follows a common pattern (%s).", structure.Mask))
+	if structure.CapitalFirst {
+		hints = append(hints, "Capitalise a letter in the middle instead of the first one.")
+	}
+	if structure.AllCaps {
+		hints = append(hints, "Mix upper- and lowercase letters instead of writing everything in capitals.")
+	}
+	if structure.DigitSuffix {
+		hints = append(hints, "Move your digits into the middle.")
+	}
+	if structure.SymbolSuffix {
+		hints = append(hints, "Move your special characters into the middle.")
+	}
+	return strings.Join(hints, " ")
+}
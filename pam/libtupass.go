@@ -23,7 +23,7 @@ func CalculateStrength(password *C.char) (s float64) {
 
 	// read password file, calculate and return result
 	api.SetupPasswordByFile(pwlist)
-	_, _, _, s, _, _, _, _ = api.CalculateMetrics(C.GoString(password))
+	s = api.CalculateMetrics(C.GoString(password), "en").Strength
 	return
 }
 
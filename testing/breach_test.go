@@ -0,0 +1,133 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// writeHashCorpus writes a hash corpus in the Pwned Passwords format for given passwords and counts to a temporary file.
+// It returns the path of the file and a function removing it again.
+func writeHashCorpus(t *testing.T, counts map[string]int) (string, func()) {
+	dir, err := ioutil.TempDir("", "tupass")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for password, count := range counts {
+		lines = append(lines, fmt.Sprintf("%s:%d\r\n", metric.HashPassword(password), count))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(dir, "pwned-passwords-sha1-ordered-by-hash.txt")
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { _ = os.RemoveAll(dir) }
+}
+
+// TestHashCorpusLookup tests the function metric.HashCorpus.Lookup().
+func TestHashCorpusLookup(t *testing.T) {
+	counts := map[string]int{}
+	for i := 0; i < 500; i++ {
+		counts[fmt.Sprintf("password%d", i)] = i + 1
+	}
+	path, cleanup := writeHashCorpus(t, counts)
+	defer cleanup()
+
+	corpus, err := metric.OpenHashCorpus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer corpus.Close()
+
+	t.Log("Testing metric.HashCorpus.Lookup()")
+	for password, expected := range counts {
+		if test, err := corpus.Lookup(metric.HashPassword(password)); err != nil || test != expected {
+			t.Errorf("output of Lookup('%s') is not as expected. \n Result: %d (error: %v) \n Expected: %d", password, test, err, expected)
+		}
+	}
+
+	testValues := []string{"", "password", "password500", "correct horse battery staple"}
+	for _, password := range testValues {
+		if test, err := corpus.Lookup(metric.HashPassword(password)); err != nil || test != 0 {
+			t.Errorf("output of Lookup('%s') is not as expected. \n Result: %d (error: %v) \n Expected: 0", password, test, err)
+		}
+	}
+}
+
+// TestCalculateBreach tests the function metric.CalculateBreach().
+func TestCalculateBreach(t *testing.T) {
+	path, cleanup := writeHashCorpus(t, map[string]int{"123456": 23547453, "Sommer2019!": 12})
+	defer cleanup()
+
+	corpus, err := metric.OpenHashCorpus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer corpus.Close()
+
+	metric.BreachCorpus = corpus
+	defer func() { metric.BreachCorpus = nil }()
+
+	testValues := []string{"123456", "Sommer2019!", "sommer2019!", "P55hj#"}
+	expectedOutput := []int{23547453, 12, 0, 0}
+
+	t.Log("Testing metric.CalculateBreach()")
+	for i := 0; i < len(expectedOutput); i++ {
+		if test := metric.CalculateBreach(testValues[i]); test != expectedOutput[i] {
+			t.Errorf("output of CalculateBreach('%s') is not as expected. \n Result: %d \n Expected: %d", testValues[i], test, expectedOutput[i])
+		}
+	}
+}
+
+// TestHashCorpusRange tests the function metric.HashCorpus.Range().
+func TestHashCorpusRange(t *testing.T) {
+	counts := map[string]int{}
+	for i := 0; i < 5000; i++ {
+		counts[fmt.Sprintf("password%d", i)] = i + 1
+	}
+	path, cleanup := writeHashCorpus(t, counts)
+	defer cleanup()
+
+	corpus, err := metric.OpenHashCorpus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer corpus.Close()
+
+	t.Log("Testing metric.HashCorpus.Range()")
+	for _, password := range []string{"password0", "password1234", "password4999"} {
+		hash := metric.HashPassword(password)
+		entries, err := corpus.Range(hash[:5])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found := false
+		for i, entry := range entries {
+			if i > 0 && entries[i-1].Suffix >= entry.Suffix {
+				t.Errorf("Range('%s') is not ordered by hash", hash[:5])
+			}
+			if entry.Suffix == hash[5:] && entry.Count == counts[password] {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("output of Range('%s') does not contain '%s:%d'", hash[:5], hash[5:], counts[password])
+		}
+	}
+
+	if entries, err := corpus.Range("FFFFF"); err != nil || len(entries) != 0 {
+		t.Errorf("output of Range('FFFFF') is not as expected. \n Result: %v (error: %v) \n Expected: []", entries, err)
+	}
+}
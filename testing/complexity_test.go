@@ -4,8 +4,10 @@ package testing
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
+	"github.com/tupass/tupass-backend/fuzzy"
 	"github.com/tupass/tupass-backend/metric"
 )
 
@@ -55,3 +57,63 @@ func TestCalculateComplexity(t *testing.T) {
 func errorMessageCalculateComplexity(input string, output float64, expected float64) string {
 	return fmt.Sprintf("output of metric.CalculateComplexity('%s') is not as expected. \n Result: %f \n Expected: %f", input, output, expected)
 }
+
+// TestCalculateDiversity tests the function metric.CalculateDiversity().
+func TestCalculateDiversity(t *testing.T) {
+	testValues := []string{"", "a", "aaaa", "aabb", "abcd", "aabbccdd", "abcdefgh", "aabbccddeeffgghh"}
+
+	// passwords shorter than 8 chars can not be fully diverse
+	expectedOutput := []float64{0, 0.0625, 0.0625, (2.0/8 + 1.0/3) / 2, (4.0/8 + 2.0/3) / 2, (0.5 + 2.0/3) / 2, 1, (0.5 + 3.0/4) / 2}
+	t.Log("Testing metric.CalculateDiversity()")
+	for i := 0; i < len(expectedOutput); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		if test := metric.CalculateDiversity(testValues[i]); math.Abs(test-expectedOutput[i]) > 1e-9 {
+			t.Error(fmt.Sprintf("output of metric.CalculateDiversity('%s') is not as expected. \n Result: %f \n Expected: %f", testValues[i], test, expectedOutput[i]))
+		}
+	}
+}
+
+// TestCalculateComplexityV2 tests the function metric.CalculateComplexityV2().
+func TestCalculateComplexityV2(t *testing.T) {
+	testValues := []string{"", "12345", "abcd", "aabb", "aB3$", "aB3$eF6&"}
+
+	digits, lowercase := 100*math.Log2(10)/math.Log2(95), 100*math.Log2(26)/math.Log2(95)
+	expectedOutput := []float64{0, digits * (5.0/8 + math.Log2(5)/3) / 2, lowercase * (4.0/8 + 2.0/3) / 2, lowercase * (2.0/8 + 1.0/3) / 2,
+		100 * (4.0/8 + 2.0/3) / 2, 100}
+	t.Log("Testing metric.CalculateComplexityV2()")
+	for i := 0; i < len(expectedOutput); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		if test := metric.CalculateComplexityV2(testValues[i]); math.Abs(test-expectedOutput[i]) > 1e-9 {
+			t.Error(fmt.Sprintf("output of metric.CalculateComplexityV2('%s') is not as expected. \n Result: %f \n Expected: %f", testValues[i], test, expectedOutput[i]))
+		}
+	}
+
+	// the repeated chars of a long password do not make it complex
+	if test := metric.CalculateComplexityV2("aaaaaaaaaaaaaaaaaaaaaaaaaA1!"); test >= 25 {
+		t.Error(fmt.Sprintf("output of metric.CalculateComplexityV2('aaaaaaaaaaaaaaaaaaaaaaaaaA1!') is not as expected. \n Result: %f \n Expected: < 25", test))
+	}
+}
+
+// TestCalculateMembershipGradesForComplexityV2 tests the function fuzzy.CalculateMembershipGradesForComplexityV2()
+// with the complexities of representative passwords of complexity model v2.
+func TestCalculateMembershipGradesForComplexityV2(t *testing.T) {
+	testValues := []string{"000000", "123456", "aB3$", "password", "qwertyuiop", "aaaaaaaaaaaaaaaaaaaaaaaaaA1!", "Xk9mPq2vL7wZ", "Xk9#mPq2$vL7wZ!"}
+
+	// grades of very simple, simple, medium, complex and very complex
+	expectedOutput := [][]float64{{1, 0, 0, 0, 0}, {1, 0, 0, 0, 0}, {0.33, 0.67, 0, 0, 0}, {0.05, 0.95, 0, 0, 0}, {0, 0.53, 0.47, 0, 0},
+		{1, 0, 0, 0, 0}, {0, 0, 0, 0.44, 0.56}, {0, 0, 0, 0, 1}}
+	t.Log("Testing fuzzy.CalculateMembershipGradesForComplexityV2()")
+	for i := 0; i < len(testValues); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		test := fuzzy.CalculateMembershipGradesForComplexityV2(metric.CalculateComplexityV2(testValues[i]))
+		for k := range expectedOutput[i] {
+			if math.Abs(test[k]-expectedOutput[i][k]) > 0.01 {
+				t.Error(fmt.Sprintf("output of fuzzy.CalculateMembershipGradesForComplexityV2() of '%s' is not as expected. \n Result: %v \n Expected: %v", testValues[i], test, expectedOutput[i]))
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestKeyboardCostModel tests the functions of metric.KeyboardCostModel.
+func TestKeyboardCostModel(t *testing.T) {
+	t.Log("Testing metric.KeyboardCostModel")
+
+	if _, err := metric.NewKeyboardCostModel("DVORAK"); err == nil {
+		t.Error("metric.NewKeyboardCostModel('DVORAK') did not return an error")
+	}
+
+	qwerty, _ := metric.NewKeyboardCostModel("QWERTY")
+	qwertz, _ := metric.NewKeyboardCostModel("QWERTZ")
+	testValues := [][2]rune{{'a', 'a'}, {'a', 'A'}, {'a', 's'}, {'a', 'S'}, {'a', 'p'}, {'t', 'y'}, {'t', 'z'}}
+
+	expectedQwerty := []float64{0, 1, 1.5, 1.5, 2, 1.5, 2}
+	expectedQwertz := []float64{0, 1, 1.5, 1.5, 2, 2, 1.5}
+	for i := 0; i < len(testValues); i++ {
+		testQwerty := qwerty.Substitution(testValues[i][0], testValues[i][1])
+		testQwertz := qwertz.Substitution(testValues[i][0], testValues[i][1])
+		if testQwerty != expectedQwerty[i] || testQwertz != expectedQwertz[i] {
+			t.Error(fmt.Sprintf("output of metric.KeyboardCostModel.Substitution('%c', '%c') is not as expected. \n Result: %f, %f \n Expected: %f, %f",
+				testValues[i][0], testValues[i][1], testQwerty, testQwertz, expectedQwerty[i], expectedQwertz[i]))
+		}
+	}
+}
+
+// TestDistanceCostModel tests the usage of metric.DistanceCostModel by metric.CalculateSimilarity().
+func TestDistanceCostModel(t *testing.T) {
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("password")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	costModel := metric.DistanceCostModel
+	defer func() { metric.DistanceCostModel = costModel }()
+
+	testValues := []string{"passowrd", "pasword", "paasword"}
+	models := []metric.CostModel{costModel, metric.LevenshteinCostModel{}}
+
+	// transpositions and substitutions of neighbouring keys are cheaper than in the classic model
+	expectedOutput := [][]float64{{1 - 1.0/16, 1 - 1.0/15, 1 - 1.5/16}, {1 - 2.0/16, 1 - 1.0/15, 1 - 2.0/16}}
+	t.Log("Testing metric.DistanceCostModel")
+	for m, model := range models {
+		metric.DistanceCostModel = model
+		for i := 0; i < len(testValues); i++ {
+			t.Logf("Testing: string: '%s'", testValues[i])
+
+			if test := metric.CalculateSimilarity(testValues[i]); math.Abs(test.Score-expectedOutput[m][i]*100) > 1e-9 {
+				t.Error(fmt.Sprintf("output of metric.CalculateSimilarity('%s') with cost model %T is not as expected. \n Result: %f \n Expected: %f",
+					testValues[i], model, test.Score, expectedOutput[m][i]*100))
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fes"
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// TestDefuzzy tests the function fuzzy.Defuzzy() with all methods on the aggregated membership function.
+func TestDefuzzy(t *testing.T) {
+	universe := fuzzy.Arrange(0, 10.5, 1)
+	// a symmetric trapezoid and a ramp
+	trapezoid := []float64{0, 0, 0, 0.5, 1, 1, 1, 0.5, 0, 0, 0}
+	ramp := []float64{0, 0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1}
+
+	testValues := []struct {
+		method   fuzzy.DefuzzificationMethod
+		YMFG     []float64
+		expected float64
+	}{
+		{fuzzy.Centroid, trapezoid, 5},
+		{fuzzy.Bisector, trapezoid, 5},
+		{fuzzy.MeanOfMaximum, trapezoid, 5},
+		{fuzzy.SmallestOfMaximum, trapezoid, 4},
+		{fuzzy.LargestOfMaximum, trapezoid, 6},
+		{fuzzy.Centroid, ramp, 20. / 3},
+		{fuzzy.Bisector, ramp, math.Sqrt(50)},
+		{fuzzy.MeanOfMaximum, ramp, 10},
+		{fuzzy.SmallestOfMaximum, ramp, 10}}
+
+	t.Log("Testing fuzzy.Defuzzy()")
+	for _, test := range testValues {
+		result, err := fuzzy.Defuzzy(universe, test.YMFG, test.method)
+		if err != nil || math.Abs(result-test.expected) > 1e-9 {
+			t.Error(fmt.Sprintf("output of fuzzy.Defuzzy(%v, %s) is not as expected. \n Result: %v (%v) \n Expected: %v", test.YMFG, test.method, result, err, test.expected))
+		}
+	}
+
+	if _, err := fuzzy.Defuzzy(universe, make([]float64, len(universe)), fuzzy.Centroid); err != fuzzy.ErrZeroDegree {
+		t.Error(fmt.Sprintf("fuzzy.Defuzzy() of zero degree did not return fuzzy.ErrZeroDegree, but %v", err))
+	}
+	if _, err := fuzzy.Defuzzy(universe, ramp, fuzzy.WeightedAverage); err == nil {
+		t.Error("fuzzy.Defuzzy() with fuzzy.WeightedAverage did not return an error")
+	}
+}
+
+// TestDefuzzyWeightedAverage tests the function fuzzy.DefuzzyWeightedAverage().
+func TestDefuzzyWeightedAverage(t *testing.T) {
+	t.Log("Testing fuzzy.DefuzzyWeightedAverage()")
+	if result, err := fuzzy.DefuzzyWeightedAverage([]float64{10, 50}, []float64{1, 3}); err != nil || result != 40 {
+		t.Error(fmt.Sprintf("output of fuzzy.DefuzzyWeightedAverage([10 50], [1 3]) is not as expected. \n Result: %v (%v) \n Expected: 40", result, err))
+	}
+	if _, err := fuzzy.DefuzzyWeightedAverage([]float64{10, 50}, []float64{0, 0}); err != fuzzy.ErrZeroDegree {
+		t.Error(fmt.Sprintf("fuzzy.DefuzzyWeightedAverage() of zero degree did not return fuzzy.ErrZeroDegree, but %v", err))
+	}
+}
+
+// TestEngineMethod tests that fuzzy.Engine.Defuzzify() uses the method of the engine and returns its default value
+// if no rule is activated.
+func TestEngineMethod(t *testing.T) {
+	engine := testEngine(t)
+	// slow clipped at 0.5 has its maxima from 0 to 25, the centres of slow and fast are 0 and 100
+	testValues := map[fuzzy.DefuzzificationMethod]float64{
+		fuzzy.MeanOfMaximum:     12.5,
+		fuzzy.SmallestOfMaximum: 0,
+		fuzzy.LargestOfMaximum:  25,
+		fuzzy.WeightedAverage:   0}
+
+	t.Log("Testing fuzzy.Engine.Defuzzify()")
+	for method, expected := range testValues {
+		engine.Method = method
+		if result, err := engine.Defuzzify([]float64{0.5, 0}); err != nil || result != expected {
+			t.Error(fmt.Sprintf("output of fuzzy.Engine.Defuzzify([0.5 0]) with method %s is not as expected. \n Result: %v (%v) \n Expected: %v", method, result, err, expected))
+		}
+	}
+	engine.Method = fuzzy.WeightedAverage
+	if result, err := engine.Defuzzify([]float64{1, 1}); err != nil || result != 50 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.Defuzzify([1 1]) with method %s is not as expected. \n Result: %v (%v) \n Expected: 50", engine.Method, result, err))
+	}
+
+	engine.Default = 42
+	for _, method := range fuzzy.DefuzzificationMethods {
+		engine.Method = method
+		if result, err := engine.Defuzzify([]float64{0, 0}); err != fuzzy.ErrZeroDegree || result != 42 {
+			t.Error(fmt.Sprintf("output of fuzzy.Engine.Defuzzify([0 0]) with method %s is not as expected. \n Result: %v (%v) \n Expected: 42 (%v)", method, result, err, fuzzy.ErrZeroDegree))
+		}
+	}
+
+	// the method is read from and written to FCL
+	parsed, err := fuzzy.ParseFCL(strings.NewReader(strings.Replace(testFCL, "METHOD : COG", "METHOD : rm", 1)))
+	if err != nil || parsed.Method != fuzzy.LargestOfMaximum {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() did not read the defuzzification method: %v", err))
+	}
+	var written strings.Builder
+	if err := fuzzy.WriteFCL(&written, parsed, "fan"); err != nil || !strings.Contains(written.String(), "METHOD : RM;") {
+		t.Error(fmt.Sprintf("fuzzy.WriteFCL() did not write the defuzzification method: %v", err))
+	}
+}
+
+// TestExactCentroid tests that the centroid calculated exactly by fuzzy.Engine.Defuzzify() for piecewise linear
+// output membership functions matches the centroid of the membership functions sampled on the universe.
+func TestExactCentroid(t *testing.T) {
+	engine := testEngine(t)
+	// slow clipped at 0.5 is a rectangle from 0 to 25 and a triangle from 25 to 50
+	if result, err := engine.Defuzzify([]float64{0.5, 0}); err != nil || math.Abs(result-175./9) > 1e-12 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.Defuzzify([0.5 0]) is not as expected. \n Result: %v (%v) \n Expected: %v", result, err, 175./9))
+	}
+
+	engine = fes.Model()
+	mfs, err := engine.Output.MembershipFunctions()
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.Variable.MembershipFunctions() failed: %v", err))
+	}
+	levels := []float64{0, 0.3, 0.5, 1}
+	grades := make([]float64, len(mfs))
+
+	t.Log("Testing fuzzy.Engine.Defuzzify()")
+	for _, implication := range []fuzzy.TNorm{fuzzy.Minimum, fuzzy.Product, fuzzy.BoundedDifference} {
+		for _, aggregation := range []fuzzy.SNorm{fuzzy.Maximum, fuzzy.Sum, fuzzy.BoundedSum} {
+			engine.Implication, engine.Aggregation = implication, aggregation
+			for combination := 1; combination < int(math.Pow(float64(len(levels)), float64(len(grades)))); combination++ {
+				for i, c := 0, combination; i < len(grades); i, c = i+1, c/len(levels) {
+					grades[i] = levels[c%len(levels)]
+				}
+
+				area := make([]float64, len(engine.Output.Universe))
+				for i, mf := range mfs {
+					for x, value := range mf {
+						area[x] = aggregation.Apply(area[x], implication.Apply(grades[i], value))
+					}
+				}
+				expected, expectedErr := fuzzy.Defuzzy(engine.Output.Universe, area, fuzzy.Centroid)
+				result, err := engine.Defuzzify(grades)
+				if err != expectedErr || math.Abs(result-expected) > samplingTolerance {
+					t.Fatal(fmt.Sprintf("output of fuzzy.Engine.Defuzzify(%v) with %s and %s is not as expected. \n Result: %v (%v) \n Expected: %v (%v)",
+						grades, implication, aggregation, result, err, expected, expectedErr))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fes"
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// testEngine returns an engine with two inputs and rules using wildcards and AND/OR/NOT.
+func testEngine(t *testing.T) *fuzzy.Engine {
+	temperature := fuzzy.Variable{Name: "temperature", Universe: fuzzy.Arrange(0, 41, 1),
+		Terms: []fuzzy.Term{
+			{Name: "cold", MF: fuzzy.Triangle{Left: 0, Middle: 0, Right: 20}},
+			{Name: "warm", MF: fuzzy.Triangle{Left: 10, Middle: 20, Right: 30}},
+			{Name: "hot", MF: fuzzy.Triangle{Left: 20, Middle: 40, Right: 40}}}}
+	humidity := fuzzy.Variable{Name: "humidity", Universe: fuzzy.Arrange(0, 101, 1),
+		Terms: []fuzzy.Term{
+			{Name: "dry", MF: fuzzy.Triangle{Left: 0, Middle: 0, Right: 100}},
+			{Name: "humid", MF: fuzzy.Triangle{Left: 0, Middle: 100, Right: 100}}}}
+	fan := fuzzy.Variable{Name: "fan", Universe: fuzzy.Arrange(0, 100.5, .5),
+		Terms: []fuzzy.Term{
+			{Name: "slow", MF: fuzzy.Triangle{Left: 0, Middle: 0, Right: 50}},
+			{Name: "fast", MF: fuzzy.Triangle{Left: 50, Middle: 100, Right: 100}}}}
+
+	rules := []fuzzy.Rule{
+		{If: fuzzy.And{fuzzy.Is{Variable: "temperature", Term: "cold"}, fuzzy.Is{Variable: "humidity", Term: fuzzy.Any}}, Then: "slow"},
+		{If: fuzzy.Or{fuzzy.Is{Variable: "temperature", Term: "hot"}, fuzzy.Is{Variable: "humidity", Term: "humid"}}, Then: "fast"},
+		{If: fuzzy.And{fuzzy.Is{Variable: "temperature", Term: "warm"}, fuzzy.Not{Antecedent: fuzzy.Is{Variable: "humidity", Term: "humid"}}}, Then: "slow"}}
+
+	engine, err := fuzzy.NewEngine([]fuzzy.Variable{temperature, humidity}, fan, rules)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.NewEngine() failed: %v", err))
+	}
+	return engine
+}
+
+// evaluateCrisp returns the output of the given engine for the given crisp values and fails the test on an error.
+func evaluateCrisp(t *testing.T, engine *fuzzy.Engine, values map[string]float64) float64 {
+	output, err := engine.EvaluateCrisp(values)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.Engine.EvaluateCrisp(%v) failed: %v", values, err))
+	}
+	return output
+}
+
+// TestEngineInfer tests the function fuzzy.Engine.Infer().
+func TestEngineInfer(t *testing.T) {
+	engine := testEngine(t)
+
+	testValues := []map[string][]float64{
+		{"temperature": {1, 0, 0}, "humidity": {0.5, 0.5}},
+		{"temperature": {0, 0.5, 0.5}, "humidity": {0.75, 0.25}},
+		{"temperature": {0, 1, 0}, "humidity": {0, 1}}}
+	expectedOutput := [][]float64{{1, 0.5}, {0.5, 0.5}, {0, 1}}
+
+	t.Log("Testing fuzzy.Engine.Infer()")
+	for i := 0; i < len(testValues); i++ {
+		test := engine.Infer(testValues[i])
+		if len(test) != len(expectedOutput[i]) || test[0] != expectedOutput[i][0] || test[1] != expectedOutput[i][1] {
+			t.Error(fmt.Sprintf("output of fuzzy.Engine.Infer(%v) is not as expected. \n Result: %v \n Expected: %v", testValues[i], test, expectedOutput[i]))
+		}
+	}
+
+	// a symmetric output is defuzzified to the middle of the universe
+	if test, err := engine.Defuzzify([]float64{1, 1}); err != nil || test < 49.99 || test > 50.01 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.Defuzzify([1 1]) is not as expected. \n Result: %f \n Expected: 50", test))
+	}
+}
+
+// TestNewEngine tests that fuzzy.NewEngine() rejects rules with unknown variables or terms.
+func TestNewEngine(t *testing.T) {
+	input := fuzzy.Variable{Name: "x", Universe: fuzzy.Arrange(0, 11, 1), Terms: []fuzzy.Term{{Name: "low", MF: fuzzy.Triangle{Left: 0, Middle: 0, Right: 10}}}}
+	output := fuzzy.Variable{Name: "y", Universe: fuzzy.Arrange(0, 11, 1), Terms: []fuzzy.Term{{Name: "low", MF: fuzzy.Triangle{Left: 0, Middle: 0, Right: 10}}}}
+
+	t.Log("Testing fuzzy.NewEngine()")
+	invalidRules := []fuzzy.Rule{
+		{If: fuzzy.Is{Variable: "z", Term: "low"}, Then: "low"},
+		{If: fuzzy.Is{Variable: "x", Term: "high"}, Then: "low"},
+		{If: fuzzy.Not{Antecedent: fuzzy.Or{fuzzy.Is{Variable: "x", Term: "high"}}}, Then: "low"},
+		{If: fuzzy.Is{Variable: "x", Term: "low"}, Then: "high"},
+		{Then: "low"}}
+	for _, rule := range invalidRules {
+		if _, err := fuzzy.NewEngine([]fuzzy.Variable{input}, output, []fuzzy.Rule{rule}); err == nil {
+			t.Error(fmt.Sprintf("fuzzy.NewEngine() with rule %v did not return an error", rule))
+		}
+	}
+}
+
+// samplingTolerance is the largest expected difference between the exact centroid of the strength and the one of its
+// membership functions sampled in steps of 0.1, which misses the corners where rules clip the output terms between
+// the samples. Over a grid of 252,000 inputs of the TUPass model, the difference reaches 0.0017 (e.g. at length 5,
+// complexity 7 and predictability 32.5), the tolerance is a twentieth of the step.
+const samplingTolerance = 5e-3
+
+// TestGetStrengthByMembershipGrades tests that fes.GetStrengthByMembershipGrades() returns the strengths of the TUPass
+// model before it was expressed by fuzzy.Engine, up to the resolution error of its sampled centroid.
+func TestGetStrengthByMembershipGrades(t *testing.T) {
+	// length, complexity, predictability and breach count
+	testValues := [][4]float64{{4, 20, 100, 0}, {8, 150, 60, 0}, {12, 300, 40, 0}, {16.5, 420, 20, 0}, {24, 600, 10, 0}, {20, 500, 65, 0}, {20, 500, 10, 1}}
+	expectedOutput := []float64{8.809523809523801, 18.58974358974357, 60.000000000000014, 75.74054472572598, 91.14013409961687, 42.72727272727272, 7.777777777777773}
+
+	t.Log("Testing fes.GetStrengthByMembershipGrades()")
+	for i := 0; i < len(testValues); i++ {
+		test := fes.GetStrengthByMembershipGrades(fuzzy.CalculateMembershipGradesForLength(testValues[i][0]), fuzzy.CalculateMembershipGradesForComplexity(testValues[i][1]),
+			fuzzy.CalculateMembershipGradesForPredictability(testValues[i][2]), fuzzy.CalculateMembershipGradesForBreach(testValues[i][3]))
+		if math.Abs(test-expectedOutput[i]) > samplingTolerance {
+			t.Error(fmt.Sprintf("output of fes.GetStrengthByMembershipGrades() for %v is not as expected. \n Result: %v \n Expected: %v", testValues[i], test, expectedOutput[i]))
+		}
+	}
+}
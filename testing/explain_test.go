@@ -0,0 +1,69 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/tupass/tupass-backend/api"
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestExplain tests the function api.Explain().
+func TestExplain(t *testing.T) {
+	passwordList := metric.PasswordList
+	defer func() { metric.PasswordList = passwordList }()
+	metric.PasswordList = [][]rune{[]rune("password"), []rune("monkey"), []rune("dragon"), []rune("passwort")}
+
+	t.Log("Testing api.Explain()")
+	for _, password := range []string{"password1", "Xk9#mPq2$vL7wZ!", "monkeydragon"} {
+		test := api.Explain(password, "en", 3)
+		if expected := api.CalculateResult(password, "en"); !reflect.DeepEqual(test.Result, expected) {
+			t.Error(fmt.Sprintf("result of api.Explain('%s') is not as expected. \n Result: %+v \n Expected: %+v", password, test.Result, expected))
+		}
+		expected := metric.CalculateSimilarPasswords(password, 3)
+		if len(test.SimilarPasswords) != len(expected) {
+			t.Error(fmt.Sprintf("similar passwords of api.Explain('%s') are not as expected. \n Result: %+v \n Expected: %+v", password, test.SimilarPasswords, expected))
+			continue
+		}
+		for i, similar := range test.SimilarPasswords {
+			if similar.Password != expected[i].Password || similar.Transform != expected[i].Transform {
+				t.Error(fmt.Sprintf("similar passwords of api.Explain('%s') are not as expected. \n Result: %+v \n Expected: %+v", password, test.SimilarPasswords, expected))
+				break
+			}
+		}
+	}
+}
+
+// TestExplainHandler tests that api.ExplainHandler() is only served with the configured token.
+func TestExplainHandler(t *testing.T) {
+	passwordList, explainToken := metric.PasswordList, api.ExplainToken
+	defer func() { metric.PasswordList, api.ExplainToken = passwordList, explainToken }()
+	metric.PasswordList = [][]rune{[]rune("password")}
+
+	testValues := []struct {
+		token, authorization, k string
+	}{{"", "", ""}, {"secret", "", ""}, {"secret", "Bearer other", ""}, {"secret", "Bearer secret", ""}, {"secret", "Bearer secret", "26"}}
+	expectedOutput := []int{http.StatusNotFound, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusOK, http.StatusBadRequest}
+
+	t.Log("Testing api.ExplainHandler()")
+	for i, value := range testValues {
+		api.ExplainToken = value.token
+		request := httptest.NewRequest("GET", "/api/explain?k="+value.k, nil)
+		request.Header.Set("password", `"password1"`)
+		request.Header.Set("language", "en")
+		if value.authorization != "" {
+			request.Header.Set("Authorization", value.authorization)
+		}
+		recorder := httptest.NewRecorder()
+		api.ExplainHandler(recorder, request)
+		if recorder.Code != expectedOutput[i] {
+			t.Error(fmt.Sprintf("status of api.ExplainHandler() with token %q, authorization %q and k %q is not as expected. \n Result: %d \n Expected: %d",
+				value.token, value.authorization, value.k, recorder.Code, expectedOutput[i]))
+		}
+	}
+}
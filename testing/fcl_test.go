@@ -0,0 +1,197 @@
+// +build unit
+
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fes"
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// testFCL is the engine of testEngine in FCL, with comments and the alternative notations of terms and conditions.
+const testFCL = `(* fan speed
+   by temperature and humidity *)
+FUNCTION_BLOCK fan
+
+VAR_INPUT
+	temperature : REAL;
+	humidity : REAL;
+END_VAR
+
+VAR_OUTPUT
+	fan : REAL;
+END_VAR
+
+FUZZIFY temperature
+	RANGE := (0 .. 40);
+	TERM cold := (0, 1) (20, 0);
+	TERM warm := trian 10 20 30;
+	TERM hot := (20, 0) (40, 1);
+END_FUZZIFY
+
+FUZZIFY humidity
+	TERM dry := (0, 1) (100, 0);
+	TERM humid := (0, 0) (100, 1);
+END_FUZZIFY
+
+DEFUZZIFY fan
+	RANGE := (0 .. 100);
+	TERM slow := trape 0 0 0 50;
+	TERM fast := (50, 0) (100, 1);
+	METHOD : COG;
+	DEFAULT := 0;
+END_DEFUZZIFY
+
+RULEBLOCK rules
+	AND : MIN;
+	ACT : MIN;
+	RULE 1 : IF temperature IS cold AND humidity IS * THEN fan IS slow; // wildcard
+	RULE 2 : IF (temperature IS hot OR humidity IS humid) THEN fan IS fast;
+	rule 3 : if temperature is warm and humidity is not humid then fan is slow;
+END_RULEBLOCK
+
+END_FUNCTION_BLOCK
+`
+
+// TestParseFCL tests that fuzzy.ParseFCL() returns the engine given in FCL and that fuzzy.WriteFCL() writes it
+// such that it is parsed to the same engine again.
+func TestParseFCL(t *testing.T) {
+	expected := testEngine(t)
+
+	t.Log("Testing fuzzy.ParseFCL()")
+	engine, err := fuzzy.ParseFCL(strings.NewReader(testFCL))
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() failed: %v", err))
+	}
+
+	var written bytes.Buffer
+	if err := fuzzy.WriteFCL(&written, engine, "fan"); err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.WriteFCL() failed: %v", err))
+	}
+	reparsed, err := fuzzy.ParseFCL(&written)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() of the output of fuzzy.WriteFCL() failed: %v\n%s", err, written.String()))
+	}
+
+	for temperature := 0.0; temperature <= 40; temperature += 5 {
+		for humidity := 0.0; humidity <= 100; humidity += 25 {
+			values := map[string]float64{"temperature": temperature, "humidity": humidity}
+			// the universe of the output differs, so do the results
+			want := evaluateCrisp(t, expected, values)
+			if test := evaluateCrisp(t, engine, values); test < want-0.5 || test > want+0.5 {
+				t.Error(fmt.Sprintf("output of parsed engine for %v is not as expected. \n Result: %f \n Expected: %f", values, test, want))
+			}
+			if test, want := evaluateCrisp(t, reparsed, values), evaluateCrisp(t, engine, values); test != want {
+				t.Error(fmt.Sprintf("output of written and parsed engine for %v is not as expected. \n Result: %v \n Expected: %v", values, test, want))
+			}
+		}
+	}
+}
+
+// TestParseFCLInvalid tests that fuzzy.ParseFCL() rejects invalid or unsupported FCL.
+func TestParseFCLInvalid(t *testing.T) {
+	replacements := [][2]string{
+		{"FUNCTION_BLOCK fan", "FUNCTION fan"},
+		{"RANGE := (0 .. 40)", "RANGE := (40 .. 0)"},
+		{"RANGE := (0 .. 40)", "RANGE := (0 .. 1e300)"},
+		{"RANGE := (0 .. 40)", "RANGE := (0 .. 1e9)"},
+		{"trian 10 20 30", "(10, 0) (20, 0.5) (30, 0)"},
+		{"trian 10 20 30", "(20, 0) (10, 1) (30, 0)"},
+		{"trian 10 20 30", "gauss 20 0"},
+		{"trian 10 20 30", "trian 30 20 10"},
+		{"METHOD : COG", "METHOD : MOM"},
+		{"AND : MIN", "AND : MAX"},
+		{"ACT : MIN;", "ACT : MIN;\n\tACT : PROD;"},
+		{"humidity IS humid)", "humidity IS wet)"},
+		{"THEN fan IS slow;", "THEN temperature IS cold;"},
+		{"fan : REAL;\nEND_VAR", "fan : REAL;\n\tspeed : REAL;\nEND_VAR"},
+		{"humidity *)", "humidity"},
+		{"END_FUNCTION_BLOCK", ""},
+		{"RULE 2 : IF (", "RULE 2 : IF (("}}
+
+	t.Log("Testing fuzzy.ParseFCL()")
+	for _, replacement := range replacements {
+		fcl := strings.Replace(testFCL, replacement[0], replacement[1], 1)
+		if _, err := fuzzy.ParseFCL(strings.NewReader(fcl)); err == nil {
+			t.Error(fmt.Sprintf("fuzzy.ParseFCL() with %s replaced by %s did not return an error", replacement[0], replacement[1]))
+		}
+	}
+}
+
+// TestWriteModel tests that fes/strength.fcl is the built-in model written by fes.WriteModel() and that it
+// is parsed to an engine returning exactly the strengths of the built-in model.
+func TestWriteModel(t *testing.T) {
+	builtIn, err := ioutil.ReadFile(filepath.Join("..", "fes", "strength.fcl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("Testing fes.WriteModel()")
+	var written bytes.Buffer
+	if err := fes.WriteModel(&written); err != nil {
+		t.Fatal(fmt.Sprintf("fes.WriteModel() failed: %v", err))
+	}
+	if written.String() != string(builtIn) {
+		t.Error("output of fes.WriteModel() is not fes/strength.fcl, run go run ./cmd/tupass-fcl > fes/strength.fcl")
+	}
+
+	engine, err := fuzzy.ParseFCL(bytes.NewReader(builtIn))
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() of fes/strength.fcl failed: %v", err))
+	}
+	for length := 0.0; length <= 28; length += 2 {
+		for complexity := 0.0; complexity <= 700; complexity += 50 {
+			for predictability := 0.0; predictability <= 100; predictability += 10 {
+				for _, breach := range []float64{0, 1} {
+					LList, CList := fuzzy.CalculateMembershipGradesForLength(length), fuzzy.CalculateMembershipGradesForComplexity(complexity)
+					PList, BList := fuzzy.CalculateMembershipGradesForPredictability(predictability), fuzzy.CalculateMembershipGradesForBreach(breach)
+					want := fes.GetStrengthByMembershipGrades(LList, CList, PList, BList)
+					values := map[string]float64{"length": length, "complexity": complexity, "predictability": predictability, "breach": breach}
+					if test := evaluateCrisp(t, engine, values); test != want {
+						t.Fatal(fmt.Sprintf("output of parsed fes/strength.fcl for %v is not as expected. \n Result: %v \n Expected: %v", values, test, want))
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestLoadModel tests that fes.LoadModel() rejects models without the variables and terms of the TUPass model.
+func TestLoadModel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tupass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	t.Log("Testing fes.LoadModel()")
+	invalidModels := []string{"", testFCL}
+	var written bytes.Buffer
+	if err := fes.WriteModel(&written); err != nil {
+		t.Fatal(err)
+	}
+	invalidModels = append(invalidModels, strings.Replace(written.String(), "\tTERM medium := (30, 0) (50, 1) (70, 0);\n", "", 1))
+
+	for i, model := range invalidModels {
+		path := filepath.Join(dir, fmt.Sprintf("model%d.fcl", i))
+		if err := ioutil.WriteFile(path, []byte(model), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := fes.LoadModel(path); err == nil {
+			t.Error(fmt.Sprintf("fes.LoadModel() of model %d did not return an error", i))
+		}
+	}
+	if err := fes.LoadModel(filepath.Join(dir, "missing.fcl")); err == nil {
+		t.Error("fes.LoadModel() of a missing file did not return an error")
+	}
+	if fes.IsModelLoaded() {
+		t.Error("fes.LoadModel() replaced the model by an invalid one")
+	}
+}
@@ -0,0 +1,94 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// fuzzificationVariables are the input variables of the TUPass model.
+var fuzzificationVariables = []fuzzy.Variable{fuzzy.LengthVariable, fuzzy.ComplexityVariable, fuzzy.ComplexityV2Variable,
+	fuzzy.PredictabilityVariable, fuzzy.BreachVariable}
+
+// TestFuzzify tests that fuzzy.Variable.Fuzzify() returns the grades interpolated on the sampled membership functions
+// (see fuzzy.DetMFGrad) for all points of the universes, the points between them and values outside of them.
+func TestFuzzify(t *testing.T) {
+	t.Log("Testing fuzzy.Variable.Fuzzify()")
+	for _, variable := range fuzzificationVariables {
+		mfs, err := variable.MembershipFunctions()
+		if err != nil {
+			t.Fatal(fmt.Sprintf("fuzzy.Variable.MembershipFunctions() of %s failed: %v", variable.Name, err))
+		}
+
+		universe := variable.Universe
+		values := []float64{universe[0] - 10, universe[0] - 0.05, universe[len(universe)-1] + 0.05, universe[len(universe)-1] + 1000}
+		for i, x := range universe {
+			values = append(values, x)
+			if i > 0 {
+				values = append(values, (universe[i-1]+x)/2, universe[i-1]+(x-universe[i-1])/3)
+			}
+		}
+
+		for _, x := range values {
+			test := variable.Fuzzify(x)
+			for i, mf := range mfs {
+				if expected := fuzzy.DetMFGrad(universe, mf, x); math.Abs(test[i]-expected) > 1e-9 {
+					t.Fatal(fmt.Sprintf("output of fuzzy.Variable(%s).Fuzzify(%v) is not as expected. \n Result: %v \n Expected: %v in term %d",
+						variable.Name, x, test, expected, i))
+				}
+			}
+		}
+	}
+}
+
+// TestAppendGradesAllocations tests that fuzzy.Variable.AppendGrades() does not allocate with a large enough slice.
+func TestAppendGradesAllocations(t *testing.T) {
+	grades := make([]float64, 0, 5)
+
+	t.Log("Testing fuzzy.Variable.AppendGrades()")
+	for _, variable := range fuzzificationVariables {
+		allocations := testing.AllocsPerRun(100, func() {
+			grades = variable.AppendGrades(grades[:0], 123.4)
+		})
+		if allocations != 0 {
+			t.Error(fmt.Sprintf("fuzzy.Variable(%s).AppendGrades() allocates. \n Result: %v allocations \n Expected: 0", variable.Name, allocations))
+		}
+	}
+}
+
+// BenchmarkAppendGrades benchmarks the fuzzification of a complexity into a reused slice.
+func BenchmarkAppendGrades(b *testing.B) {
+	grades := make([]float64, 0, len(fuzzy.ComplexityVariable.Terms))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		grades = fuzzy.ComplexityVariable.AppendGrades(grades[:0], float64(i%680))
+	}
+}
+
+// BenchmarkCalculateMembershipGradesForComplexity benchmarks the fuzzification of a complexity.
+func BenchmarkCalculateMembershipGradesForComplexity(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fuzzy.CalculateMembershipGradesForComplexity(float64(i % 680))
+	}
+}
+
+// BenchmarkCalculateMembershipGradesForLength benchmarks the fuzzification of a length.
+func BenchmarkCalculateMembershipGradesForLength(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fuzzy.CalculateMembershipGradesForLength(float64(i % 27))
+	}
+}
+
+// BenchmarkCalculateMembershipGradesForPredictability benchmarks the fuzzification of a predictability.
+func BenchmarkCalculateMembershipGradesForPredictability(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fuzzy.CalculateMembershipGradesForPredictability(float64(i % 100))
+	}
+}
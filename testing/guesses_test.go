@@ -0,0 +1,52 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestEstimateGuesses tests the function metric.EstimateGuesses().
+func TestEstimateGuesses(t *testing.T) {
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("password"), []rune("123456"), []rune("dragon")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	testValues := []string{"", "123456", "dragon", "aB3$"}
+	expectedGuesses := []float64{1, 2, 3, 26 * 26 * 10 * 33}
+
+	t.Log("Testing metric.EstimateGuesses()")
+	for i := 0; i < len(testValues); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		test := metric.EstimateGuesses(testValues[i], metric.CalculateSegmentation(testValues[i]))
+		if math.Abs(test-expectedGuesses[i]) > 1e-6*expectedGuesses[i] {
+			t.Error(fmt.Sprintf("output of metric.EstimateGuesses('%s') is not as expected. \n Result: %g \n Expected: %g",
+				testValues[i], test, expectedGuesses[i]))
+		}
+	}
+}
+
+// TestFormatDuration tests the function metric.FormatDuration().
+func TestFormatDuration(t *testing.T) {
+	testValues := []float64{0.5, 1, 59, 3600, 2 * 24 * 3600, 50 * 365.2425 * 24 * 3600, 1e20}
+
+	expectedEn := []string{"less than a second", "1 second", "59 seconds", "1 hour", "2 days", "50 years", "many centuries"}
+	expectedDe := []string{"weniger als eine Sekunde", "1 Sekunde", "59 Sekunden", "1 Stunde", "2 Tage", "50 Jahre", "viele Jahrhunderte"}
+
+	t.Log("Testing metric.FormatDuration()")
+	for i := 0; i < len(testValues); i++ {
+		t.Logf("Testing: seconds: %g", testValues[i])
+
+		testEn := metric.FormatDuration(testValues[i], "en")
+		testDe := metric.FormatDuration(testValues[i], "de")
+		if testEn != expectedEn[i] || testDe != expectedDe[i] {
+			t.Error(fmt.Sprintf("output of metric.FormatDuration(%g) is not as expected. \n Result: '%s', '%s' \n Expected: '%s', '%s'",
+				testValues[i], testEn, testDe, expectedEn[i], expectedDe[i]))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// TestHedges tests the function fuzzy.Hedge.Apply().
+func TestHedges(t *testing.T) {
+	expectedOutput := map[fuzzy.Hedge]float64{fuzzy.Very: 0.0625, fuzzy.Extremely: 0.015625, fuzzy.Somewhat: 0.5, fuzzy.NotHedge: 0.75}
+
+	t.Log("Testing fuzzy.Hedge.Apply()")
+	for _, hedge := range fuzzy.Hedges {
+		if test := hedge.Apply(0.25); math.Abs(test-expectedOutput[hedge]) > 1e-12 {
+			t.Error(fmt.Sprintf("output of fuzzy.Hedge(%s).Apply(0.25) is not as expected. \n Result: %v \n Expected: %v", hedge, test, expectedOutput[hedge]))
+		}
+	}
+}
+
+// TestRuleWeightsAndHedges tests that fuzzy.Engine.Infer() applies the weights of rules and the hedges of their
+// conditions and that they are read from and written to FCL.
+func TestRuleWeightsAndHedges(t *testing.T) {
+	engine := testEngine(t)
+	rules := append([]fuzzy.Rule{}, engine.Rules...)
+	rules[0].If = fuzzy.And{fuzzy.Is{Variable: "temperature", Term: "cold", Hedges: []fuzzy.Hedge{fuzzy.Very}}, fuzzy.Is{Variable: "humidity", Term: fuzzy.Any}}
+	rules[1].Weight = 0.5
+	rules[2].If = fuzzy.And{fuzzy.Is{Variable: "temperature", Term: "warm"},
+		fuzzy.Not{Antecedent: fuzzy.Is{Variable: "humidity", Term: "humid", Hedges: []fuzzy.Hedge{fuzzy.Somewhat}}}}
+	engine, err := fuzzy.NewEngine(engine.Inputs, engine.Output, rules)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.NewEngine() failed: %v", err))
+	}
+
+	// slow: rule 1 (very cold) 0.5² and rule 3 (warm and not somewhat humid) min(0.5, 1-√0.25), fast: rule 2 0.5·0.25
+	grades := map[string][]float64{"temperature": {0.5, 0.5, 0}, "humidity": {0.75, 0.25}}
+	expectedOutput := []float64{0.5, 0.125}
+
+	t.Log("Testing fuzzy.Engine.Infer()")
+	if test := engine.Infer(grades); test[0] != expectedOutput[0] || test[1] != expectedOutput[1] {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.Infer(%v) is not as expected. \n Result: %v \n Expected: %v", grades, test, expectedOutput))
+	}
+
+	var written strings.Builder
+	if err := fuzzy.WriteFCL(&written, engine, "fan"); err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.WriteFCL() failed: %v", err))
+	}
+	for _, rule := range []string{"IF temperature IS very cold THEN", "humidity IS humid THEN fan IS fast WITH 0.5;", "humidity IS NOT somewhat humid THEN"} {
+		if !strings.Contains(written.String(), rule) {
+			t.Error(fmt.Sprintf("fuzzy.WriteFCL() did not write the rule %q:\n%s", rule, written.String()))
+		}
+	}
+	parsed, err := fuzzy.ParseFCL(strings.NewReader(written.String()))
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() failed: %v", err))
+	}
+	if test := parsed.Infer(grades); test[0] != expectedOutput[0] || test[1] != expectedOutput[1] {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.Infer(%v) of the written rules is not as expected. \n Result: %v \n Expected: %v", grades, test, expectedOutput))
+	}
+
+	// a term named like a hedge
+	parsed, err = fuzzy.ParseFCL(strings.NewReader(strings.Replace(testFCL, "hot", "very", -1)))
+	if err != nil || parsed.Rules[1].If.(fuzzy.Or)[0].(fuzzy.Is).Term != "very" {
+		t.Error(fmt.Sprintf("fuzzy.ParseFCL() did not read the term named very: %v", err))
+	}
+}
+
+// TestRuleWeightsAndHedgesInvalid tests that fuzzy.NewEngine() and fuzzy.ParseFCL() reject invalid weights and hedges.
+func TestRuleWeightsAndHedgesInvalid(t *testing.T) {
+	engine := testEngine(t)
+	invalidRules := []fuzzy.Rule{
+		{If: fuzzy.Is{Variable: "temperature", Term: "cold"}, Then: "slow", Weight: 1.5},
+		{If: fuzzy.Is{Variable: "temperature", Term: "cold"}, Then: "slow", Weight: -0.5},
+		{If: fuzzy.Is{Variable: "temperature", Term: "cold", Hedges: []fuzzy.Hedge{"rather"}}, Then: "slow"},
+		{If: fuzzy.Is{Variable: "temperature", Term: fuzzy.Any, Hedges: []fuzzy.Hedge{fuzzy.Very}}, Then: "slow"}}
+
+	t.Log("Testing fuzzy.NewEngine()")
+	for _, rule := range invalidRules {
+		if _, err := fuzzy.NewEngine(engine.Inputs, engine.Output, []fuzzy.Rule{rule}); err == nil {
+			t.Error(fmt.Sprintf("fuzzy.NewEngine() with rule %v did not return an error", rule))
+		}
+	}
+
+	t.Log("Testing fuzzy.ParseFCL()")
+	for _, replacement := range []string{"fan IS slow WITH 2;", "fan IS slow WITH 0;", "fan IS slow WITH;"} {
+		if _, err := fuzzy.ParseFCL(strings.NewReader(strings.Replace(testFCL, "fan IS slow;", replacement, 1))); err == nil {
+			t.Error(fmt.Sprintf("fuzzy.ParseFCL() with %q did not return an error", replacement))
+		}
+	}
+}
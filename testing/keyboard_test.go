@@ -0,0 +1,41 @@
+// +build unit
+
+package testing
+
+import (
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestCalculateKeyboardWalks tests the function metric.CalculateKeyboardWalks().
+func TestCalculateKeyboardWalks(t *testing.T) {
+	testValues := []string{"qwertzuiop", "yxcvbnm", "1qay", "!\"§$%", "qwsa", "azerty", "ABCqazxsw"}
+	expectedOutput := []metric.KeyboardWalk{
+		{Start: 0, End: 10, Token: "qwertzuiop", Layout: "QWERTZ", Kind: metric.WalkStraight},
+		{Start: 0, End: 7, Token: "yxcvbnm", Layout: "QWERTZ", Kind: metric.WalkStraight},
+		{Start: 0, End: 4, Token: "1qay", Layout: "QWERTZ", Kind: metric.WalkDiagonal},
+		{Start: 0, End: 5, Token: "!\"§$%", Layout: "QWERTZ", Kind: metric.WalkStraight, Shifted: 5},
+		{Start: 0, End: 4, Token: "qwsa", Layout: "QWERTY", Kind: metric.WalkSnake, Turns: 2},
+		{Start: 0, End: 6, Token: "azerty", Layout: "AZERTY", Kind: metric.WalkStraight},
+		{Start: 3, End: 9, Token: "qazxsw", Layout: "QWERTY", Kind: metric.WalkSnake, Turns: 2},
+	}
+
+	t.Log("Testing metric.CalculateKeyboardWalks()")
+	for i := 0; i < len(expectedOutput); i++ {
+		found := false
+		for _, walk := range metric.CalculateKeyboardWalks(testValues[i]) {
+			walk.Entropy = 0
+			if walk == expectedOutput[i] {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("output of CalculateKeyboardWalks('%s') does not contain %+v", testValues[i], expectedOutput[i])
+		}
+	}
+
+	if walks := metric.CalculateKeyboardWalks("Xk9#mPq2"); len(walks) != 0 {
+		t.Errorf("output of CalculateKeyboardWalks('Xk9#mPq2') is not as expected. \n Result: %+v \n Expected: []", walks)
+	}
+}
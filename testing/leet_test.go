@@ -0,0 +1,64 @@
+// +build unit
+
+package testing
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestParseLeetTable tests the functions metric.ParseLeetTable() and metric.WriteLeetTable().
+func TestParseLeetTable(t *testing.T) {
+	t.Log("Testing metric.ParseLeetTable()")
+
+	table, err := metric.ParseLeetTable(strings.NewReader("# comment\n\nh\t|-|\t1.5\nf\tph\n"))
+	expected := []metric.LeetSubstitution{{Plain: "h", Leet: "|-|", Cost: 1.5}, {Plain: "f", Leet: "ph", Cost: 1}}
+	if err != nil || !reflect.DeepEqual(table, expected) {
+		t.Error(fmt.Sprintf("output of metric.ParseLeetTable() is not as expected. \n Result: %v, %v \n Expected: %v", table, err, expected))
+	}
+
+	var buffer bytes.Buffer
+	if err := metric.WriteLeetTable(&buffer, metric.DefaultLeetTable); err != nil {
+		t.Error(fmt.Sprintf("metric.WriteLeetTable() failed: %v", err))
+	}
+	if table, err := metric.ParseLeetTable(&buffer); err != nil || !reflect.DeepEqual(table, metric.DefaultLeetTable) {
+		t.Error(fmt.Sprintf("output of metric.ParseLeetTable() of the written default table is not as expected. \n Result: %v, %v", table, err))
+	}
+
+	for _, invalid := range []string{"", "h|-|\n", "h\t|-|\tcheap\n"} {
+		if _, err := metric.ParseLeetTable(strings.NewReader(invalid)); err == nil {
+			t.Error(fmt.Sprintf("metric.ParseLeetTable('%s') did not return an error", invalid))
+		}
+	}
+}
+
+// TestSetLeetTable tests the function metric.SetLeetTable().
+func TestSetLeetTable(t *testing.T) {
+	defer metric.SetLeetTable(metric.DefaultLeetTable)
+
+	t.Log("Testing metric.SetLeetTable()")
+	invalidTables := [][]metric.LeetSubstitution{{{Plain: "", Leet: "4", Cost: 1}}, {{Plain: "a", Leet: "4", Cost: 0}}, {{Plain: "1", Leet: "l", Cost: 1}}}
+	for _, table := range invalidTables {
+		if err := metric.SetLeetTable(table); err == nil {
+			t.Error(fmt.Sprintf("metric.SetLeetTable(%v) did not return an error", table))
+		}
+	}
+
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("phone")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	// multi-rune tokens are substituted at their cost
+	if err := metric.SetLeetTable([]metric.LeetSubstitution{{Plain: "h", Leet: "|-|", Cost: 1.5}}); err != nil {
+		t.Error(fmt.Sprintf("metric.SetLeetTable() failed: %v", err))
+	}
+	if test := metric.CalculateSimilarity("p|-|one"); math.Abs(test.Score-(1-1.5/12)*100) > 1e-9 {
+		t.Error(fmt.Sprintf("output of metric.CalculateSimilarity('p|-|one') is not as expected. \n Result: %f \n Expected: %f", test.Score, (1-1.5/12)*100))
+	}
+}
@@ -0,0 +1,32 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestCalculateMangling tests the function metric.CalculateMangling().
+func TestCalculateMangling(t *testing.T) {
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("password"), []rune("monkey"), []rune("dragon")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	testValues := []string{"monkey", "Password1!", "drowssap", "dr4g0n", "monkey2019", "12dragon", "MONKEYMONKEY", "xyzzy"}
+
+	expectedWord := []string{"monkey", "password", "password", "dragon", "monkey", "dragon", "monkey", ""}
+	expectedNotation := []string{"", "$1 $! c", "r", "so0 sa4", "$2 $0 $1 $9", "^2 ^1", "d u", ""}
+	t.Log("Testing metric.CalculateMangling()")
+	for i := 0; i < len(testValues); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		test := metric.CalculateMangling(testValues[i])
+		if test.Word != expectedWord[i] || test.Notation() != expectedNotation[i] {
+			t.Error(fmt.Sprintf("output of metric.CalculateMangling('%s') is not as expected. \n Result: '%s', '%s' \n Expected: '%s', '%s'",
+				testValues[i], test.Word, test.Notation(), expectedWord[i], expectedNotation[i]))
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// TestMembershipFunctions tests the function Grade() of the shapes of fuzzy.MembershipFunction.
+func TestMembershipFunctions(t *testing.T) {
+	testValues := []struct {
+		mf       fuzzy.MembershipFunction
+		x        []float64
+		expected []float64
+	}{
+		{fuzzy.Triangle{Left: 2, Middle: 4.5, Right: 5}, []float64{1, 2, 3.25, 4.5, 4.75, 5, 6}, []float64{0, 0, 0.5, 1, 0.5, 0, 0}},
+		{fuzzy.Triangle{Left: 2, Middle: 2, Right: 6}, []float64{0, 2, 4, 6}, []float64{1, 1, 0.5, 0}},
+		{fuzzy.Trapezoid{Left: 0, LeftTop: 2, RightTop: 4, Right: 8}, []float64{-1, 1, 2, 3, 4, 6, 8}, []float64{0, 0.5, 1, 1, 1, 0.5, 0}},
+		{fuzzy.Trapezoid{Left: 22, LeftTop: 26, RightTop: 30, Right: 30}, []float64{20, 24, 28, 40}, []float64{0, 0.5, 1, 1}},
+		{fuzzy.Gaussian{Mean: 10, Sigma: 2}, []float64{10, 12, 6}, []float64{1, math.Exp(-0.5), math.Exp(-2)}},
+		{fuzzy.Bell{Width: 2, Slope: 1, Center: 10}, []float64{10, 12, 6}, []float64{1, 0.5, 0.2}},
+		{fuzzy.Sigmoid{Slope: 2, Center: 10}, []float64{10, 11, 9}, []float64{0.5, 1 / (1 + math.Exp(-2)), 1 / (1 + math.Exp(2))}},
+		{fuzzy.Sigmoid{Slope: -2, Center: 10}, []float64{10, 11, 9}, []float64{0.5, 1 / (1 + math.Exp(2)), 1 / (1 + math.Exp(-2))}}}
+
+	t.Log("Testing fuzzy.MembershipFunction.Grade()")
+	for _, test := range testValues {
+		if err := test.mf.Validate(); err != nil {
+			t.Error(fmt.Sprintf("%#v.Validate() returned an error: %v", test.mf, err))
+		}
+		for i, x := range test.x {
+			if grade := test.mf.Grade(x); math.Abs(grade-test.expected[i]) > 1e-12 {
+				t.Error(fmt.Sprintf("output of %#v.Grade(%v) is not as expected. \n Result: %v \n Expected: %v", test.mf, x, grade, test.expected[i]))
+			}
+		}
+	}
+}
+
+// TestMembershipFunctionsInvalid tests that fuzzy.MembershipFunction.Validate() and fuzzy.DetMF() reject invalid parameters.
+func TestMembershipFunctionsInvalid(t *testing.T) {
+	invalid := []fuzzy.MembershipFunction{
+		fuzzy.Triangle{Left: 3, Middle: 2, Right: 4},
+		fuzzy.Triangle{Left: 0, Middle: math.NaN(), Right: 4},
+		fuzzy.Trapezoid{Left: 0, LeftTop: 3, RightTop: 2, Right: 4},
+		fuzzy.Trapezoid{Left: math.Inf(-1), LeftTop: 0, RightTop: 2, Right: 4},
+		fuzzy.Gaussian{Mean: 10, Sigma: 0},
+		fuzzy.Bell{Width: -1, Slope: 1, Center: 0},
+		fuzzy.Bell{Width: 1, Slope: 0, Center: 0},
+		fuzzy.Sigmoid{Slope: 0, Center: 10},
+		nil}
+
+	t.Log("Testing fuzzy.MembershipFunction.Validate()")
+	for _, mf := range invalid {
+		if _, err := fuzzy.DetMF(fuzzy.Arrange(0, 10, 1), mf); err == nil {
+			t.Error(fmt.Sprintf("fuzzy.DetMF() with %#v did not return an error", mf))
+		}
+	}
+}
+
+// TestFCLShapes tests that fuzzy.WriteFCL() and fuzzy.ParseFCL() keep the membership functions of all shapes.
+func TestFCLShapes(t *testing.T) {
+	universe := fuzzy.Arrange(0, 20.05, .1)
+	input := fuzzy.Variable{Name: "x", Universe: universe, Terms: []fuzzy.Term{
+		{Name: "low", MF: fuzzy.Sigmoid{Slope: -1.5, Center: 5}},
+		{Name: "middle", MF: fuzzy.Gaussian{Mean: 10, Sigma: 2.5}},
+		{Name: "high", MF: fuzzy.Bell{Width: 3, Slope: 2, Center: 17.5}}}}
+	output := fuzzy.Variable{Name: "y", Universe: universe, Terms: []fuzzy.Term{
+		{Name: "low", MF: fuzzy.Trapezoid{Left: 0, LeftTop: 0, RightTop: 5, Right: 10}},
+		{Name: "high", MF: fuzzy.Triangle{Left: 5, Middle: 15, Right: 20}}}}
+	engine, err := fuzzy.NewEngine([]fuzzy.Variable{input}, output, []fuzzy.Rule{
+		{If: fuzzy.Or{fuzzy.Is{Variable: "x", Term: "low"}, fuzzy.Is{Variable: "x", Term: "middle"}}, Then: "low"},
+		{If: fuzzy.Is{Variable: "x", Term: "high"}, Then: "high"}})
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.NewEngine() failed: %v", err))
+	}
+
+	t.Log("Testing fuzzy.WriteFCL() and fuzzy.ParseFCL()")
+	var written strings.Builder
+	if err := fuzzy.WriteFCL(&written, engine, "shapes"); err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.WriteFCL() failed: %v", err))
+	}
+	parsed, err := fuzzy.ParseFCL(strings.NewReader(written.String()))
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() failed: %v\n%s", err, written.String()))
+	}
+	for i, term := range parsed.Inputs[0].Terms {
+		if term != input.Terms[i] {
+			t.Error(fmt.Sprintf("term %s of parsed variable x is not as expected. \n Result: %#v \n Expected: %#v", term.Name, term.MF, input.Terms[i].MF))
+		}
+	}
+	for x := 0.0; x <= 20; x += 0.5 {
+		if test, want := evaluateCrisp(t, parsed, map[string]float64{"x": x}), evaluateCrisp(t, engine, map[string]float64{"x": x}); test != want {
+			t.Error(fmt.Sprintf("output of parsed engine for x = %v is not as expected. \n Result: %v \n Expected: %v", x, test, want))
+		}
+	}
+
+	// smooth shapes do not bound the range of a variable
+	if _, err := fuzzy.ParseFCL(strings.NewReader(strings.Replace(written.String(), "\tRANGE := (0 .. 20);\n", "", 1))); err == nil {
+		t.Error("fuzzy.ParseFCL() of a variable with smooth shapes and without range did not return an error")
+	}
+}
@@ -0,0 +1,86 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// TestTNorms tests the function fuzzy.TNorm.Apply() and that all t-norms have 1 as identity and are commutative.
+func TestTNorms(t *testing.T) {
+	expectedOutput := map[fuzzy.TNorm]float64{fuzzy.Minimum: 0.4, fuzzy.Product: 0.2, fuzzy.BoundedDifference: 0, fuzzy.HamacherProduct: 0.2 / 0.7}
+
+	t.Log("Testing fuzzy.TNorm.Apply()")
+	for _, tnorm := range fuzzy.TNorms {
+		if test := tnorm.Apply(0.5, 0.4); math.Abs(test-expectedOutput[tnorm]) > 1e-12 {
+			t.Error(fmt.Sprintf("output of fuzzy.TNorm(%s).Apply(0.5, 0.4) is not as expected. \n Result: %v \n Expected: %v", tnorm, test, expectedOutput[tnorm]))
+		}
+		for _, a := range []float64{0, 0.3, 1} {
+			if test := tnorm.Apply(a, 1); math.Abs(test-a) > 1e-12 {
+				t.Error(fmt.Sprintf("output of fuzzy.TNorm(%s).Apply(%v, 1) is not as expected. \n Result: %v \n Expected: %v", tnorm, a, test, a))
+			}
+			if tnorm.Apply(a, 0.7) != tnorm.Apply(0.7, a) {
+				t.Error(fmt.Sprintf("fuzzy.TNorm(%s).Apply() is not commutative for %v and 0.7", tnorm, a))
+			}
+		}
+	}
+}
+
+// TestSNorms tests the function fuzzy.SNorm.Apply() and that all s-norms have 0 as identity and are commutative.
+func TestSNorms(t *testing.T) {
+	expectedOutput := map[fuzzy.SNorm]float64{fuzzy.Maximum: 0.5, fuzzy.ProbabilisticSum: 0.7, fuzzy.BoundedSum: 0.9,
+		fuzzy.HamacherSum: 0.5 / 0.8, fuzzy.Sum: 0.9}
+
+	t.Log("Testing fuzzy.SNorm.Apply()")
+	for _, snorm := range fuzzy.SNorms {
+		if test := snorm.Apply(0.5, 0.4); math.Abs(test-expectedOutput[snorm]) > 1e-12 {
+			t.Error(fmt.Sprintf("output of fuzzy.SNorm(%s).Apply(0.5, 0.4) is not as expected. \n Result: %v \n Expected: %v", snorm, test, expectedOutput[snorm]))
+		}
+		for _, a := range []float64{0, 0.3, 1} {
+			if test := snorm.Apply(a, 0); math.Abs(test-a) > 1e-12 {
+				t.Error(fmt.Sprintf("output of fuzzy.SNorm(%s).Apply(%v, 0) is not as expected. \n Result: %v \n Expected: %v", snorm, a, test, a))
+			}
+			if snorm.Apply(a, 0.7) != snorm.Apply(0.7, a) {
+				t.Error(fmt.Sprintf("fuzzy.SNorm(%s).Apply() is not commutative for %v and 0.7", snorm, a))
+			}
+		}
+	}
+}
+
+// TestEngineOperators tests that fuzzy.Engine.Infer() uses the operators of the engine and that they are read from
+// and written to FCL.
+func TestEngineOperators(t *testing.T) {
+	engine := testEngine(t)
+	engine.And, engine.Or, engine.Aggregation = fuzzy.Product, fuzzy.ProbabilisticSum, fuzzy.ProbabilisticSum
+
+	// slow: rule 1 (cold and any) 0.5·1 and rule 3 (warm and not humid) 0.5·0.75, fast: rule 2 (hot or humid) 0.5+0.25-0.125
+	grades := map[string][]float64{"temperature": {0.5, 0.5, 0.5}, "humidity": {0.75, 0.25}}
+	expectedOutput := []float64{0.5 + 0.375 - 0.5*0.375, 0.625}
+
+	t.Log("Testing fuzzy.Engine.Infer()")
+	test := engine.Infer(grades)
+	for i := range expectedOutput {
+		if math.Abs(test[i]-expectedOutput[i]) > 1e-12 {
+			t.Error(fmt.Sprintf("output of fuzzy.Engine.Infer(%v) is not as expected. \n Result: %v \n Expected: %v", grades, test, expectedOutput))
+		}
+	}
+
+	fcl := strings.NewReplacer("AND : MIN", "AND : PROD", "ACT : MIN", "ACT : prod;\n\tACCU : SUM").Replace(testFCL)
+	parsed, err := fuzzy.ParseFCL(strings.NewReader(fcl))
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() failed: %v", err))
+	}
+	if parsed.And != fuzzy.Product || parsed.Or != "" || parsed.Implication != fuzzy.Product || parsed.Aggregation != fuzzy.Sum {
+		t.Error(fmt.Sprintf("operators of fuzzy.ParseFCL() are not as expected. \n Result: %s %s %s %s \n Expected: PROD  PROD SUM",
+			parsed.And, parsed.Or, parsed.Implication, parsed.Aggregation))
+	}
+	var written strings.Builder
+	if err := fuzzy.WriteFCL(&written, parsed, "fan"); err != nil || !strings.Contains(written.String(), "AND : PROD;\n\tOR : MAX;\n\tACT : PROD;\n\tACCU : SUM;") {
+		t.Error(fmt.Sprintf("fuzzy.WriteFCL() did not write the operators: %v\n%s", err, written.String()))
+	}
+}
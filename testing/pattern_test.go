@@ -0,0 +1,58 @@
+// +build unit
+
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestCalculatePatterns tests the function metric.CalculatePatterns().
+func TestCalculatePatterns(t *testing.T) {
+	now := metric.Now
+	defer func() { metric.Now = now }()
+	metric.Now = func() time.Time { return time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC) }
+
+	testValues := []string{"Sommer2026!", "Max01.04.1990", "x12/31/99", "1990-04-01", "anna01041990", "+4915112345", "ab1212", "x2580x", "März"}
+	expectedOutput := []metric.Match{
+		{Pattern: metric.PatternSeason, Start: 0, End: 6, Token: "Sommer"},
+		{Pattern: metric.PatternDate, Start: 3, End: 13, Token: "01.04.1990"},
+		{Pattern: metric.PatternDate, Start: 1, End: 9, Token: "12/31/99"},
+		{Pattern: metric.PatternDate, Start: 0, End: 10, Token: "1990-04-01"},
+		{Pattern: metric.PatternDate, Start: 4, End: 12, Token: "01041990"},
+		{Pattern: metric.PatternPhone, Start: 0, End: 11, Token: "+4915112345"},
+		{Pattern: metric.PatternDigitRepeat, Start: 2, End: 6, Token: "1212"},
+		{Pattern: metric.PatternPIN, Start: 1, End: 5, Token: "2580"},
+		{Pattern: metric.PatternMonth, Start: 0, End: 4, Token: "März"},
+	}
+
+	t.Log("Testing metric.CalculatePatterns()")
+	for i := 0; i < len(expectedOutput); i++ {
+		found := false
+		for _, match := range metric.CalculatePatterns(testValues[i]) {
+			expected := expectedOutput[i]
+			if match.Pattern == expected.Pattern && match.Start == expected.Start && match.End == expected.End && match.Token == expected.Token {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("output of CalculatePatterns('%s') does not contain %+v", testValues[i], expectedOutput[i])
+		}
+	}
+
+	// recent years are easier to guess than old ones
+	var recent, old float64
+	for _, match := range metric.CalculatePatterns("2025x1975") {
+		if match.Pattern == metric.PatternYear && match.Token == "2025" {
+			recent = match.Entropy
+		}
+		if match.Pattern == metric.PatternYear && match.Token == "1975" {
+			old = match.Entropy
+		}
+	}
+	if recent == 0 || old == 0 || recent >= old {
+		t.Errorf("entropy of recent year is not lower than entropy of old year. \n Result: %f (2025), %f (1975)", recent, old)
+	}
+}
@@ -0,0 +1,77 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestColognePhonetic tests the function metric.ColognePhonetic().
+func TestColognePhonetic(t *testing.T) {
+	testValues := []string{"Müller-Lüdenscheidt", "Wikipedia", "Breschnew", "Schatzi", "Schatzy", "Schazi", "Fohnix", "Phoenix"}
+	expectedOutput := []string{"65752682", "3412", "17863", "88", "88", "88", "3648", "3648"}
+
+	t.Log("Testing metric.ColognePhonetic()")
+	for i := 0; i < len(testValues); i++ {
+		if test := metric.ColognePhonetic(testValues[i]); test != expectedOutput[i] {
+			t.Error(fmt.Sprintf("output of metric.ColognePhonetic('%s') is not as expected. \n Result: %s \n Expected: %s", testValues[i], test, expectedOutput[i]))
+		}
+	}
+}
+
+// TestDoubleMetaphone tests the function metric.DoubleMetaphone().
+func TestDoubleMetaphone(t *testing.T) {
+	testValues := []string{"Smith", "Schmidt", "Michael", "Xavier", "Knight", "Gallegos", "sugar", "philip"}
+	expectedOutput := [][2]string{{"SM0", "XMT"}, {"XMT", "SMT"}, {"MKL", "MXL"}, {"SF", "SFR"}, {"NT", "NT"}, {"KLKS", "KKS"}, {"XKR", "SKR"}, {"FLP", "FLP"}}
+
+	t.Log("Testing metric.DoubleMetaphone()")
+	for i := 0; i < len(testValues); i++ {
+		if primary, alternate := metric.DoubleMetaphone(testValues[i]); primary != expectedOutput[i][0] || alternate != expectedOutput[i][1] {
+			t.Error(fmt.Sprintf("output of metric.DoubleMetaphone('%s') is not as expected. \n Result: %s, %s \n Expected: %s, %s",
+				testValues[i], primary, alternate, expectedOutput[i][0], expectedOutput[i][1]))
+		}
+	}
+}
+
+// TestCalculatePhoneticSimilarity tests the function metric.CalculatePhoneticSimilarity().
+func TestCalculatePhoneticSimilarity(t *testing.T) {
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("schatzi"), []rune("phoenix"), []rune("jennifer")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	testValues := []string{"Schazy", "Schazy12", "fenix", "Jenifer", "Schatzi", "qwertz"}
+	languages := []string{"de", "de", "en", "en", "de", "de"}
+	// the similarity of the letters weighted by their share, reduced by the penalty of 10 percent
+	expectedOutput := []metric.PhoneticMatch{
+		{Score: ((1-3.0/13)*6/6 - 0.1) * 100, MostSimilarPassword: "schatzi", Code: "88"},
+		{Score: ((1-3.0/13)*6/8 - 0.1) * 100, MostSimilarPassword: "schatzi", Code: "88"},
+		{Score: ((1-2.0/12)*5/5 - 0.1) * 100, MostSimilarPassword: "phoenix", Code: "FNKS"},
+		{Score: ((1-1.0/15)*7/7 - 0.1) * 100, MostSimilarPassword: "jennifer", Code: "JNFR"},
+		{},
+		{}}
+
+	t.Log("Testing metric.CalculatePhoneticSimilarity()")
+	for i := 0; i < len(testValues); i++ {
+		if test := metric.CalculatePhoneticSimilarity(testValues[i], languages[i]); math.Abs(test.Score-expectedOutput[i].Score) > 1e-9 ||
+			test.MostSimilarPassword != expectedOutput[i].MostSimilarPassword || test.Code != expectedOutput[i].Code {
+			t.Error(fmt.Sprintf("output of metric.CalculatePhoneticSimilarity('%s', '%s') is not as expected. \n Result: %v \n Expected: %v",
+				testValues[i], languages[i], test, expectedOutput[i]))
+		}
+	}
+
+	// the hint names the entry unless the password is at least as similar to an entry in spelling
+	if hint := metric.GetHintPhonetic(expectedOutput[0], metric.Similarity{Score: 60, MostSimilarPassword: "schatz"}, "en"); hint != "It sounds like 'schatzi' in our password list, which helps attackers." {
+		t.Error(fmt.Sprintf("output of metric.GetHintPhonetic() is not as expected. \n Result: %s", hint))
+	}
+	if hint := metric.GetHintPhonetic(expectedOutput[0], metric.Similarity{Score: 95, MostSimilarPassword: "schatzi1"}, "en"); hint != "" {
+		t.Error(fmt.Sprintf("output of metric.GetHintPhonetic() is not as expected. \n Result: %s \n Expected: ''", hint))
+	}
+	// the hint of the similarity already names the entry most similar in spelling
+	if hint := metric.GetHintPhonetic(expectedOutput[0], metric.Similarity{Score: 50, MostSimilarPassword: "schatzi"}, "en"); hint != "" {
+		t.Error(fmt.Sprintf("output of metric.GetHintPhonetic() is not as expected. \n Result: %s \n Expected: ''", hint))
+	}
+}
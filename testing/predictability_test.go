@@ -0,0 +1,87 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tupass/tupass-backend/api"
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestCalculateSimilarity tests the function metric.CalculateSimilarity().
+func TestCalculateSimilarity(t *testing.T) {
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("password"), []rune("monkey")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	testValues := []string{"password", "drowssap", "wordpass", "monkeymonkey", "passpass"}
+
+	expectedScore := []float64{100, 95, 95, 95, (1-4.0/12-0.05)*100}
+	expectedPassword := []string{"password", "password", "password", "monkey", "password"}
+	expectedTransform := []string{metric.TransformNone, metric.TransformReverse, metric.TransformRotation, metric.TransformDuplicate, metric.TransformDuplicate}
+	t.Log("Testing metric.CalculateSimilarity()")
+	for i := 0; i < len(testValues); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		test := metric.CalculateSimilarity(testValues[i])
+		if math.Abs(test.Score-expectedScore[i]) > 1e-9 || test.MostSimilarPassword != expectedPassword[i] || test.Transform != expectedTransform[i] {
+			t.Error(fmt.Sprintf("output of metric.CalculateSimilarity('%s') is not as expected. \n Result: %f, '%s', '%s' \n Expected: %f, '%s', '%s'",
+				testValues[i], test.Score, test.MostSimilarPassword, test.Transform, expectedScore[i], expectedPassword[i], expectedTransform[i]))
+		}
+	}
+}
+
+// TestCalculateSimilarPasswords tests the function metric.CalculateSimilarPasswords().
+func TestCalculateSimilarPasswords(t *testing.T) {
+	passwordList, passwordListSources := metric.PasswordList, metric.PasswordListSources
+	metric.PasswordList = [][]rune{[]rune("passwort"), []rune("password"), []rune("password1"), []rune("Password"), []rune("pass"), []rune("passwort")}
+	metric.PasswordListSources = []metric.PasswordListSource{{Label: "top", Start: 0}, {Label: "extra", Start: 3}}
+	defer func() { metric.PasswordList, metric.PasswordListSources = passwordList, passwordListSources }()
+
+	// equally similar entries are ordered by their position in the list
+	expectedPassword := []string{"password", "password1", "Password", "passwort", "passwort"}
+	expectedLabel := []string{"top", "top", "extra", "top", "extra"}
+	expectedRank := []int{2, 3, 1, 1, 3}
+	expectedDistance := []float64{0, 1, 1, 2, 2}
+	expectedSimilarity := []float64{100, (1 - 1.0/17) * 100, (1 - 1.0/16) * 100, (1 - 2.0/16) * 100, (1 - 2.0/16) * 100}
+
+	t.Log("Testing metric.CalculateSimilarPasswords()")
+	test := metric.CalculateSimilarPasswords("password", 5)
+	if len(test) != len(expectedPassword) {
+		t.Fatal(fmt.Sprintf("output of metric.CalculateSimilarPasswords('password', 5) is not as expected. \n Result: %v", test))
+	}
+	for i := range test {
+		if test[i].Password != expectedPassword[i] || test[i].Label != expectedLabel[i] || test[i].Rank != expectedRank[i] ||
+			test[i].Distance != expectedDistance[i] || math.Abs(test[i].Similarity-expectedSimilarity[i]) > 1e-9 {
+			t.Error(fmt.Sprintf("entry %d of metric.CalculateSimilarPasswords('password', 5) is not as expected. \n Result: '%s', '%s', %d, %f, %f \n Expected: '%s', '%s', %d, %f, %f",
+				i, test[i].Password, test[i].Label, test[i].Rank, test[i].Distance, test[i].Similarity,
+				expectedPassword[i], expectedLabel[i], expectedRank[i], expectedDistance[i], expectedSimilarity[i]))
+		}
+	}
+
+	if test := metric.CalculateSimilarPasswords("password", 2); len(test) != 2 || test[1].Password != "password1" {
+		t.Error(fmt.Sprintf("output of metric.CalculateSimilarPasswords('password', 2) is not as expected. \n Result: %v", test))
+	}
+}
+
+// BenchmarkCalculateSimilarPasswords benchmarks the comparison of passwords of different lengths to the shipped password list.
+func BenchmarkCalculateSimilarPasswords(b *testing.B) {
+	passwordList, passwordListSources := metric.PasswordList, metric.PasswordListSources
+	metric.PasswordList, metric.PasswordListSources = nil, nil
+	defer func() { metric.PasswordList, metric.PasswordListSources = passwordList, passwordListSources }()
+	api.SetupPasswordList()
+
+	for _, k := range []int{1, 25} {
+		for _, password := range []string{"p4ssw0rd", "wordpass1234", "Xk9#mPq2$vL7wZ!aB3$Tz8@nQ5^rW1&y"} {
+			b.Run(fmt.Sprintf("k=%d/%s", k, password), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					metric.CalculateSimilarPasswords(password, k)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestCalculateRepetition tests the function metric.CalculateRepetition().
+func TestCalculateRepetition(t *testing.T) {
+	testValues := []string{"", "passwort", "aaaaaaaaaaaaaaaa", "abcabcabc", "abcdefgh12345678", "x9753y", "P55hj#"}
+
+	expectedReduced := []string{"", "passwort", "a", "abc", "a1", "x9y", "P55hj#"}
+	expectedLength := []float64{0, 8, 5, 3 + math.Log2(3), 8, 5, 6}
+
+	t.Log("Testing metric.CalculateRepetition()")
+	for i := 0; i < len(expectedReduced); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		test := metric.CalculateRepetition(testValues[i])
+		if test.Reduced != expectedReduced[i] || math.Abs(test.EffectiveLength-expectedLength[i]) > 1e-9 {
+			t.Error(fmt.Sprintf("output of metric.CalculateRepetition('%s') is not as expected. \n Result: '%s', %f \n Expected: '%s', %f",
+				testValues[i], test.Reduced, test.EffectiveLength, expectedReduced[i], expectedLength[i]))
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// +build unit
+
+package testing
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestCalculateSegmentation tests the function metric.CalculateSegmentation().
+func TestCalculateSegmentation(t *testing.T) {
+	passwordList := metric.PasswordList
+	defer func() { metric.PasswordList = passwordList }()
+	metric.PasswordList = [][]rune{[]rune("123456"), []rune("password"), []rune("monkey"), []rune("dragon"), []rune("horse"), []rune("correct")}
+
+	testValues := []string{"monkeydragon", "correcthorsedragon2019!", "P4ssw0rd", "Xk9#mPq2$vL7wZ!", "asdfmonkey"}
+	expectedWords := [][]string{{"monkey", "dragon"}, {"correct", "horse", "dragon"}, {"password"}, nil, {"monkey"}}
+
+	t.Log("Testing metric.CalculateSegmentation()")
+	for i := 0; i < len(expectedWords); i++ {
+		segmentation := metric.CalculateSegmentation(testValues[i])
+		if test := segmentation.Words(); !reflect.DeepEqual(test, expectedWords[i]) {
+			t.Errorf("words of CalculateSegmentation('%s') are not as expected. \n Result: %v \n Expected: %v", testValues[i], test, expectedWords[i])
+		}
+		if expectedWords[i] == nil && segmentation.Predictability() != 0 {
+			t.Errorf("predictability of CalculateSegmentation('%s') is not as expected. \n Result: %f \n Expected: 0", testValues[i], segmentation.Predictability())
+		}
+		if expected := metric.CalculateKeyboardWalks(testValues[i]); !reflect.DeepEqual(segmentation.KeyboardWalks, expected) {
+			t.Errorf("keyboard walks of CalculateSegmentation('%s') are not as expected. \n Result: %v \n Expected: %v", testValues[i], segmentation.KeyboardWalks, expected)
+		}
+		if expected := metric.CalculatePatterns(testValues[i]); !reflect.DeepEqual(segmentation.Patterns, expected) {
+			t.Errorf("patterns of CalculateSegmentation('%s') are not as expected. \n Result: %v \n Expected: %v", testValues[i], segmentation.Patterns, expected)
+		}
+	}
+}
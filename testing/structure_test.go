@@ -0,0 +1,69 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tupass/tupass-backend/metric"
+)
+
+// TestClassMask tests the function metric.ClassMask().
+func TestClassMask(t *testing.T) {
+	testValues := []string{"", "Password1!", "P@$$w0rt", "Äoderä"}
+
+	expectedOutput := []string{"", "Ulllllllds", "Usssldll", "Ulllll"}
+	t.Log("Testing metric.ClassMask()")
+	for i := 0; i < len(expectedOutput); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		if test := metric.ClassMask(testValues[i]); test != expectedOutput[i] {
+			t.Error(fmt.Sprintf("output of metric.ClassMask('%s') is not as expected. \n Result: '%s' \n Expected: '%s'", testValues[i], test, expectedOutput[i]))
+		}
+	}
+}
+
+// TestCalculateStructure tests the function metric.CalculateStructure().
+func TestCalculateStructure(t *testing.T) {
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("Dragon1"), []rune("monkey"), []rune("PASSWORD"), []rune("12345")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	testValues := []string{"Password1!", "PassWord1!", "pass1word!", "MONKEY", "monkey", "Butterfly2024", "2024Butterfly"}
+
+	// the template is the mask normalised in length, so "Butterfly2024" follows "Dragon1", but "2024Butterfly" does not
+	expectedTemplate := []string{"Uln", "UlUln", "lnln", "U", "l", "Uln", "nUl"}
+	expectedFrequency := []float64{0.25, 0, 0, 0.25, 0.25, 0.25, 0}
+	expectedPredictable := []bool{true, false, false, true, false, true, false}
+	t.Log("Testing metric.CalculateStructure()")
+	for i := 0; i < len(testValues); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		test := metric.CalculateStructure(testValues[i])
+		if test.Template != expectedTemplate[i] || test.Frequency != expectedFrequency[i] || test.Predictable() != expectedPredictable[i] {
+			t.Error(fmt.Sprintf("output of metric.CalculateStructure('%s') is not as expected. \n Result: '%s', %f, %t \n Expected: '%s', %f, %t",
+				testValues[i], test.Template, test.Frequency, test.Predictable(), expectedTemplate[i], expectedFrequency[i], expectedPredictable[i]))
+		}
+	}
+}
+
+// TestCalculateStructuralComplexity tests the function metric.CalculateStructuralComplexity().
+func TestCalculateStructuralComplexity(t *testing.T) {
+	passwordList := metric.PasswordList
+	metric.PasswordList = [][]rune{[]rune("Dragon1"), []rune("monkey")}
+	defer func() { metric.PasswordList = passwordList }()
+
+	testValues := []string{"Password1!", "pass1word!", "P@$$w0rt"}
+
+	// appended digits and special characters and the capital first letter do not count as character sets
+	expectedOutput := []float64{62.75, 188.25, 213}
+	t.Log("Testing metric.CalculateStructuralComplexity()")
+	for i := 0; i < len(testValues); i++ {
+		t.Logf("Testing: string: '%s'", testValues[i])
+
+		if test := metric.CalculateStructuralComplexity(testValues[i], metric.CalculateStructure(testValues[i])); test != expectedOutput[i] {
+			t.Error(fmt.Sprintf("output of metric.CalculateStructuralComplexity('%s') is not as expected. \n Result: %f \n Expected: %f", testValues[i], test, expectedOutput[i]))
+		}
+	}
+}
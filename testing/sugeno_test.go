@@ -0,0 +1,105 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fes"
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// TestEngineSugeno tests the function fuzzy.Engine.Sugeno() with zero- and first-order consequents.
+func TestEngineSugeno(t *testing.T) {
+	engine := testEngine(t)
+	engine.Consequents = map[string]fuzzy.Consequent{"slow": {Constant: 20}, "fast": {Constant: 80}}
+
+	t.Log("Testing fuzzy.Engine.Sugeno()")
+	// rule 1 (cold) and rule 3 (warm and not humid) conclude slow with 0.5, rule 2 (hot or humid) fast with 0.25
+	grades := map[string][]float64{"temperature": {0.5, 0.5, 0}, "humidity": {0.75, 0.25}}
+	if test, err := engine.Evaluate(grades); err != nil || test != 32 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.Evaluate(%v) in Sugeno mode is not as expected. \n Result: %v (%v) \n Expected: 32", grades, test, err))
+	}
+
+	// only rule 2 is activated at 30°C, its first-order consequent is 10 + 2·30
+	engine.Consequents["fast"] = fuzzy.Consequent{Constant: 10, Coefficients: map[string]float64{"temperature": 2}}
+	values := map[string]float64{"temperature": 30, "humidity": 50}
+	if test := evaluateCrisp(t, engine, values); test != 70 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.EvaluateCrisp(%v) in Sugeno mode is not as expected. \n Result: %v \n Expected: 70", values, test))
+	}
+	if _, err := engine.Evaluate(grades); err == nil {
+		t.Error("fuzzy.Engine.Evaluate() with first-order consequents did not return an error")
+	}
+
+	engine.Default = 42
+	if test, err := engine.Evaluate(map[string][]float64{"temperature": {0, 0, 0}, "humidity": {0, 0}}); err != fuzzy.ErrZeroDegree || test != 42 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.Evaluate() without activated rules is not as expected. \n Result: %v (%v) \n Expected: 42 (%v)", test, err, fuzzy.ErrZeroDegree))
+	}
+}
+
+// TestDeriveConsequents tests the function fuzzy.Engine.DeriveConsequents() and zero-order Sugeno models in FCL.
+func TestDeriveConsequents(t *testing.T) {
+	engine := testEngine(t)
+
+	t.Log("Testing fuzzy.Engine.DeriveConsequents()")
+	consequents, err := engine.DeriveConsequents()
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.Engine.DeriveConsequents() failed: %v", err))
+	}
+	// centroids of the triangles (0, 1) (50, 0) and (50, 0) (100, 1)
+	expectedOutput := map[string]float64{"slow": 50. / 3, "fast": 50 + 100./3}
+	for term, expected := range expectedOutput {
+		if test := consequents[term]; math.Abs(test.Constant-expected) > 1e-9 || len(test.Coefficients) > 0 {
+			t.Error(fmt.Sprintf("consequent of term %s is not as expected. \n Result: %v \n Expected: %v", term, test, expected))
+		}
+	}
+
+	engine.Consequents = consequents
+	var written strings.Builder
+	if err := fuzzy.WriteFCL(&written, engine, "fan"); err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.WriteFCL() failed: %v", err))
+	}
+	if !strings.Contains(written.String(), "\tTERM slow := 16.66666667;\n") || !strings.Contains(written.String(), "\tMETHOD : COGS;\n") {
+		t.Error(fmt.Sprintf("fuzzy.WriteFCL() did not write the consequents as singletons:\n%s", written.String()))
+	}
+	parsed, err := fuzzy.ParseFCL(strings.NewReader(written.String()))
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.ParseFCL() failed: %v", err))
+	}
+	if !parsed.IsSugeno() || math.Abs(parsed.Consequents["fast"].Constant-expectedOutput["fast"]) > 1e-7 {
+		t.Error(fmt.Sprintf("fuzzy.ParseFCL() of singletons did not return a Sugeno model: %v", parsed.Consequents))
+	}
+}
+
+// TestSetInference tests that fes.SetInference() switches the strength model between Mamdani and Sugeno inference.
+func TestSetInference(t *testing.T) {
+	weak := [4]float64{4, 20, 100, 0}
+	strong := [4]float64{24, 600, 10, 0}
+	strength := func(values [4]float64) float64 {
+		return fes.GetStrengthByMembershipGrades(fuzzy.CalculateMembershipGradesForLength(values[0]), fuzzy.CalculateMembershipGradesForComplexity(values[1]),
+			fuzzy.CalculateMembershipGradesForPredictability(values[2]), fuzzy.CalculateMembershipGradesForBreach(values[3]))
+	}
+	mamdani := strength(strong)
+
+	t.Log("Testing fes.SetInference()")
+	if err := fes.SetInference(fes.Sugeno); err != nil {
+		t.Fatal(fmt.Sprintf("fes.SetInference(%s) failed: %v", fes.Sugeno, err))
+	}
+	weakStrength, strongStrength := strength(weak), strength(strong)
+	if err := fes.SetInference(fes.Mamdani); err != nil {
+		t.Fatal(fmt.Sprintf("fes.SetInference(%s) failed: %v", fes.Mamdani, err))
+	}
+
+	if weakStrength > 20 || strongStrength < 80 || strongStrength == mamdani {
+		t.Error(fmt.Sprintf("strengths in Sugeno mode are not as expected. \n Result: %v, %v \n Expected: at most 20, at least 80", weakStrength, strongStrength))
+	}
+	if test := strength(strong); test != mamdani {
+		t.Error(fmt.Sprintf("strength after fes.SetInference(%s) is not as expected. \n Result: %v \n Expected: %v", fes.Mamdani, test, mamdani))
+	}
+	if err := fes.SetInference("tsukamoto"); err == nil {
+		t.Error("fes.SetInference() of an unknown mode did not return an error")
+	}
+}
@@ -7,11 +7,11 @@ sleep 2s
 
 # curl API and save response
 RESPONSE=$(curl -H "password: \"test\"" -H "language: en" http://localhost:8000/api)
-EXPECTED='{"length":{"score":15,"message":"very short","hint":"Your input has the length 4. That'"'"'s very bad."},"complexity":{"score":4,"message":"very simple","hint":"Your password has very few uppercase letters, digits and special characters."},"predictability":{"score":100,"message":"easy to predict","hint":"Your password is very similar to '"'test'"' in our password list."},"strength":{"score":9,"message":"very weak","hint":""}}'
+EXPECTED='{"length":{"score":15,"message":"very short","hint":"Your input has the length 4. That'"'"'s very bad."},"complexity":{"score":4,"message":"very simple","hint":"Your password has very few uppercase letters, digits and special characters."},"predictability":{"score":100,"message":"easy to predict","hint":"Your password is very similar to '"'test'"' in our password list."},"strength":{"score":9,"message":"very weak","hint":""},"guesses":{"guesses":118,"guessesLog10":2.07,"crackTimes":[{"scenario":"online-throttled","seconds":2124,"display":"35 minutes"},{"scenario":"online-unthrottled","seconds":5.9,"display":"5 seconds"},{"scenario":"offline-bcrypt","seconds":0.0059,"display":"less than a second"},{"scenario":"offline-fast-hash","seconds":5.9e-9,"display":"less than a second"}],"hint":"An attacker would need about 10^2 guesses to find your password: 35 minutes online with rate limiting, 5 seconds online without rate limiting, less than a second offline against bcrypt, less than a second offline against a fast hash."}}'
 echo "Got: $RESPONSE"
 
 # test for expected result
-(echo $RESPONSE | grep -q "$EXPECTED")
+(echo $RESPONSE | grep -qF "$EXPECTED")
 
 # save exit code / result and stop backend
 RESULT=$?
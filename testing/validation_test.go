@@ -0,0 +1,68 @@
+// +build unit
+
+package testing
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tupass/tupass-backend/fuzzy"
+)
+
+// TestAnalyzeRules tests the function fuzzy.Engine.AnalyzeRules().
+func TestAnalyzeRules(t *testing.T) {
+	engine := testEngine(t)
+
+	t.Log("Testing fuzzy.Engine.AnalyzeRules()")
+	// cold and humid activates rule 1 (slow) and rule 2 (fast)
+	expected := fuzzy.RuleAnalysis{Combinations: 6, Conflicts: [][2]int{{0, 1}}}
+	if test := engine.AnalyzeRules(); !reflect.DeepEqual(test, expected) {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.AnalyzeRules() is not as expected. \n Result: %+v \n Expected: %+v", test, expected))
+	}
+
+	// without rule 2, hot is uncovered, cold and hot is unreachable and warm overlaps rule 3
+	rules := []fuzzy.Rule{engine.Rules[0], engine.Rules[2],
+		{If: fuzzy.And{fuzzy.Is{Variable: "temperature", Term: "cold"}, fuzzy.Is{Variable: "temperature", Term: "hot"}}, Then: "fast"},
+		{If: fuzzy.Is{Variable: "temperature", Term: "warm"}, Then: "slow"}}
+	engine, err := fuzzy.NewEngine(engine.Inputs, engine.Output, rules)
+	if err != nil {
+		t.Fatal(fmt.Sprintf("fuzzy.NewEngine() failed: %v", err))
+	}
+	expected = fuzzy.RuleAnalysis{Combinations: 6, Uncovered: [][]int{{2, 0}, {2, 1}}, Overlaps: [][2]int{{1, 3}}, Unreachable: []int{2}}
+	if test := engine.AnalyzeRules(); !reflect.DeepEqual(test, expected) {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.AnalyzeRules() is not as expected. \n Result: %+v \n Expected: %+v", test, expected))
+	}
+}
+
+// TestCheckMonotonicity tests the function fuzzy.Engine.CheckMonotonicity().
+func TestCheckMonotonicity(t *testing.T) {
+	engine := testEngine(t)
+
+	t.Log("Testing fuzzy.Engine.CheckMonotonicity()")
+	// the fan speed increases with the humidity, but from cold to warm, the centroid of slow moves to the left
+	if test, err := engine.CheckMonotonicity("humidity", false, 11, 0); err != nil || len(test) != 0 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.CheckMonotonicity(humidity) is not as expected. \n Result: %v (%v) \n Expected: no violations", test, err))
+	}
+	if test, err := engine.CheckMonotonicity("humidity", true, 11, 0); err != nil || len(test) == 0 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.CheckMonotonicity(humidity) of decreasing speed is not as expected. \n Result: %v (%v) \n Expected: violations", test, err))
+	}
+	test, err := engine.CheckMonotonicity("temperature", false, 11, 0)
+	expected := fuzzy.MonotonicityViolation{Lower: map[string]float64{"temperature": 12, "humidity": 0}, Higher: map[string]float64{"temperature": 16, "humidity": 0},
+		LowerOutput: 20.416666666666664, HigherOutput: 18.571428571428573}
+	if err != nil || len(test) == 0 || !reflect.DeepEqual(test[0].Lower, expected.Lower) || !reflect.DeepEqual(test[0].Higher, expected.Higher) ||
+		math.Abs(test[0].LowerOutput-expected.LowerOutput) > 1e-9 || math.Abs(test[0].HigherOutput-expected.HigherOutput) > 1e-9 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.CheckMonotonicity(temperature) is not as expected. \n Result: %v (%v) \n Expected: first %v", test, err, expected))
+	}
+	if test, err := engine.CheckMonotonicity("temperature", false, 11, 100); err != nil || len(test) != 0 {
+		t.Error(fmt.Sprintf("output of fuzzy.Engine.CheckMonotonicity(temperature) with tolerance is not as expected. \n Result: %v (%v) \n Expected: no violations", test, err))
+	}
+
+	if _, err := engine.CheckMonotonicity("pressure", false, 11, 0); err == nil {
+		t.Error("fuzzy.Engine.CheckMonotonicity() of an unknown input did not return an error")
+	}
+	if _, err := engine.CheckMonotonicity("humidity", false, 1, 0); err == nil {
+		t.Error("fuzzy.Engine.CheckMonotonicity() with 1 sample did not return an error")
+	}
+}
@@ -27,6 +27,12 @@ func StartServer(serverPort string) {
 	router.HandleFunc("/api/", api.RequestHandler).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api", api.RequestHandler).Methods("GET", "OPTIONS")
 
+	// explain the result in detail (e.g. the most similar passwords) for admin tooling (if an explain token is configured)
+	router.HandleFunc("/api/explain", api.ExplainHandler).Methods("GET")
+
+	// serve hash ranges of the breach corpus like the Pwned Passwords range API
+	router.HandleFunc("/range/{prefix}", api.RangeHandler).Methods("GET")
+
 	if localBuild == "true" {
 		//handle language redirection
 		router.HandleFunc("/", RedirectLanguageHandler)